@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,11 +23,7 @@ type AccountData struct {
 	DisplayName string    `json:"display_name"`
 }
 
-type AccountResponse struct {
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    AccountData `json:"data"`
-}
+type AccountResponse = Response[AccountData]
 
 type AccountsClient interface {
 	FetchParentAccount(ctx context.Context) (AccountResponse, error)
@@ -36,44 +34,25 @@ type AccountsClient interface {
 }
 
 func (c *client) FetchParentAccount(ctx context.Context) (data AccountResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/users/%s", ParentAccountID), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	return c.fetchAccount(ctx, ParentAccountID)
 }
 
 func (c *client) FetchAccount(ctx context.Context, id uuid.UUID) (data AccountResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/users/%s", id), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	return c.fetchAccount(ctx, id.String())
 }
 
-type AccountsResponse struct {
-	Status  string        `json:"status"`
-	Message string        `json:"message"`
-	Data    []AccountData `json:"data"`
+func (c *client) fetchAccount(ctx context.Context, user string) (data AccountResponse, err error) {
+	return call[AccountData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s", user), nil, nil, http.StatusOK)
 }
 
+type AccountsResponse = Response[[]AccountData]
+
 func (c *client) FetchAccounts(ctx context.Context, page int) (data AccountsResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/v1/users", nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.AddQueryParam("per_page", strconv.Itoa(c.perPage))
-	req.AddQueryParam("page", strconv.Itoa(page))
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	query.Set("page", strconv.Itoa(page))
+
+	return call[[]AccountData](ctx, c, http.MethodGet, "/v1/users", query, nil, http.StatusOK)
 }
 
 type CreateAccountPayload struct {
@@ -83,14 +62,9 @@ type CreateAccountPayload struct {
 }
 
 func (c *client) CreateAccount(ctx context.Context, payload CreateAccountPayload) (data AccountResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, "/v1/users", payload)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.ExpectStatus(http.StatusCreated)
-	req.DecodeTo(&data)
-	return data, c.do(ctx, req)
+	defer c.audit(ctx, AuditCreateAccount, "", payload, &data, &err, time.Now())
+
+	return call[AccountData](ctx, c, http.MethodPost, "/v1/users", nil, payload, http.StatusCreated)
 }
 
 type UpdateAccountPayload struct {
@@ -100,12 +74,11 @@ type UpdateAccountPayload struct {
 }
 
 func (c *client) UpdateAccount(ctx context.Context, id uuid.UUID, payload UpdateAccountPayload) (data AccountResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/v1/users/%s", id), payload)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.ExpectStatus(http.StatusOK)
-	req.DecodeTo(&data)
-	return data, c.do(ctx, req)
+	return c.updateAccount(ctx, id.String(), payload)
+}
+
+func (c *client) updateAccount(ctx context.Context, user string, payload UpdateAccountPayload) (data AccountResponse, err error) {
+	defer c.audit(ctx, AuditUpdateAccount, user, payload, &data, &err, time.Now())
+
+	return call[AccountData](ctx, c, http.MethodPut, fmt.Sprintf("/v1/users/%s", user), nil, payload, http.StatusOK)
 }
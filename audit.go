@@ -0,0 +1,222 @@
+package quidax
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	AuditCreateAccount    = "create_account"
+	AuditUpdateAccount    = "update_account"
+	AuditCreateWithdrawal = "create_withdrawal"
+	AuditQuote            = "quote"
+	AuditConfirmQuote     = "confirm_quote"
+)
+
+type AuditEntry struct {
+	Operation  string          `json:"operation"`
+	Actor      string          `json:"actor"`
+	User       string          `json:"user,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
+
+// AuditSink records the mutating operations of the client.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that carries the actor recorded in the audit entries.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithAuditSink sets the sink every mutating operation is recorded in.
+func WithAuditSink(s AuditSink) ClientOption {
+	return func(target *client) {
+		target.auditSink = s
+	}
+}
+
+// WithAuditFailClosed makes mutating operations return ErrAuditFailed when their audit entry can't be recorded,
+// instead of only logging the failure. The operation itself has already been sent by then.
+func WithAuditFailClosed() ClientOption {
+	return func(target *client) {
+		target.auditFailClosed = true
+	}
+}
+
+// audit is meant to be deferred, so result and err are read once the operation finished.
+func (c *client) audit(ctx context.Context, operation, user string, payload, result interface{}, err *error, startedAt time.Time) {
+	if c.auditSink == nil {
+		return
+	}
+
+	entry := AuditEntry{
+		Operation:  operation,
+		Actor:      ActorFromContext(ctx),
+		User:       user,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+
+	if payload != nil {
+		entry.Payload, _ = json.Marshal(payload)
+	}
+
+	if result != nil {
+		entry.Result, _ = json.Marshal(result)
+	}
+
+	if *err != nil {
+		entry.Error = (*err).Error()
+	}
+
+	recordErr := c.auditSink.Record(ctx, entry)
+	if recordErr == nil {
+		return
+	}
+
+	if c.logger != nil {
+		c.logger.WithContext(ctx).WithError(recordErr).WithField("quidax.audit.operation", operation).Error("quidax.client -> audit failed")
+	}
+
+	if c.auditFailClosed {
+		*err = errors.Join(*err, fmt.Errorf("%w: %w", ErrAuditFailed, recordErr))
+	}
+}
+
+var (
+	ErrAuditChainBroken = errors.New("audit log hash chain is broken")
+	ErrAuditFailed      = errors.New("failed to record audit entry")
+)
+
+type auditRecord struct {
+	AuditEntry
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+func auditHash(prevHash string, entry AuditEntry) (string, error) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(prevHash), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+var _ AuditSink = (*FileAuditSink)(nil)
+
+// FileAuditSink appends entries to a JSON lines file, every line carries the hash of the previous one,
+// so editing or removing a line breaks the chain, see VerifyAuditLog.
+type FileAuditSink struct {
+	mu       sync.Mutex
+	file     *os.File
+	lastHash string
+}
+
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	lastHash, err := verifyAuditLog(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &FileAuditSink{file: f, lastHash: lastHash}, nil
+}
+
+func (s *FileAuditSink) Record(ctx context.Context, entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, err := auditHash(s.lastHash, entry)
+	if err != nil {
+		return fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+
+	b, err := json.Marshal(auditRecord{AuditEntry: entry, PrevHash: s.lastHash, Hash: hash})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	s.lastHash = hash
+	return nil
+}
+
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+// VerifyAuditLog checks the hash chain of a log written by FileAuditSink.
+func VerifyAuditLog(r io.Reader) error {
+	_, err := verifyAuditLog(r)
+	return err
+}
+
+func verifyAuditLog(r io.Reader) (lastHash string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return "", fmt.Errorf("%w: line %d: %v", ErrAuditChainBroken, line, err)
+		}
+
+		hash, err := auditHash(lastHash, record.AuditEntry)
+		if err != nil {
+			return "", fmt.Errorf("%w: line %d: %v", ErrAuditChainBroken, line, err)
+		}
+
+		if record.PrevHash != lastHash || record.Hash != hash {
+			return "", fmt.Errorf("%w: line %d", ErrAuditChainBroken, line)
+		}
+
+		lastHash = hash
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return lastHash, nil
+}
@@ -0,0 +1,98 @@
+package quidax_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	sink, err := quidax.NewFileAuditSink(path)
+	require.NoError(t, err)
+
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithAuditSink(sink))
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Quote expired"}`)))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	ctx := quidax.WithActor(context.TODO(), "support@example.com")
+
+	_, err = client.CreateWithdrawal(ctx, uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+
+	err = client.ConfirmQuote(ctx, uuid.New(), uuid.New())
+	require.Error(t, err)
+	require.NoError(t, sink.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, quidax.VerifyAuditLog(bytes.NewReader(b)))
+
+	var entries []quidax.AuditEntry
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var entry quidax.AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, quidax.AuditCreateWithdrawal, entries[0].Operation)
+	assert.Equal(t, "support@example.com", entries[0].Actor)
+	assert.Contains(t, string(entries[0].Result), "ref-123")
+	assert.Equal(t, quidax.AuditConfirmQuote, entries[1].Operation)
+	assert.Contains(t, entries[1].Error, "Quote expired")
+
+	tampered := strings.Replace(string(b), `"amount":"0.1"`, `"amount":"0.2"`, 1)
+	assert.ErrorIs(t, quidax.VerifyAuditLog(strings.NewReader(tampered)), quidax.ErrAuditChainBroken)
+
+	require.NoError(t, os.WriteFile(path, []byte(tampered), 0o600))
+	_, err = quidax.NewFileAuditSink(path)
+	assert.ErrorIs(t, err, quidax.ErrAuditChainBroken)
+}
+
+type failingAuditSink struct{}
+
+func (failingAuditSink) Record(ctx context.Context, entry quidax.AuditEntry) error {
+	return errors.New("disk full")
+}
+
+func TestWithAuditFailClosed(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithAuditSink(failingAuditSink{}))
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err, "audit failures are only logged by default")
+
+	resp = &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	client = quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithAuditSink(failingAuditSink{}), quidax.WithAuditFailClosed())
+	got, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	assert.ErrorIs(t, err, quidax.ErrAuditFailed)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Equal(t, "ref-123", got.Data.Reference)
+}
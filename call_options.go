@@ -0,0 +1,88 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header WithIdempotencyKey sets.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrReservedHeader is returned by calls with a WithHeader option for a header the client sets itself.
+var ErrReservedHeader = errors.New("header is reserved by the client")
+
+// reservedHeaders are set by the client on every request and can't be changed per call.
+var reservedHeaders = []string{"Authorization", "Content-Type"}
+
+type callOptions struct {
+	timeout     time.Duration
+	headers     http.Header
+	skipLogging bool
+}
+
+// CallOption is a function that configures a single call, see WithCallOptions.
+type CallOption func(*callOptions)
+
+// WithCallTimeout sets the deadline of the call, the deadline of the context still applies when it is earlier.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(target *callOptions) {
+		target.timeout = d
+	}
+}
+
+// WithHeader adds the header to the request. The Authorization and Content-Type headers are set by the client,
+// calls with them fail with ErrReservedHeader.
+func WithHeader(key, value string) CallOption {
+	return func(target *callOptions) {
+		target.headers.Add(key, value)
+	}
+}
+
+// WithIdempotencyKey sets the key the API uses to recognize retries of the same request.
+func WithIdempotencyKey(key string) CallOption {
+	return func(target *callOptions) {
+		target.headers.Set(IdempotencyKeyHeader, key)
+	}
+}
+
+// WithoutLogging keeps the request and response of the call out of the logs, e.g. for calls with sensitive data.
+func WithoutLogging() CallOption {
+	return func(target *callOptions) {
+		target.skipLogging = true
+	}
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx that carries the options, every call made with it applies them.
+// Options are added to the ones ctx already carries.
+func WithCallOptions(ctx context.Context, options ...CallOption) context.Context {
+	o := callOptionsFromContext(ctx)
+	o.headers = o.headers.Clone()
+	if o.headers == nil {
+		o.headers = make(http.Header)
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+func callOptionsFromContext(ctx context.Context) callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return o
+}
+
+func (o callOptions) validate() error {
+	for _, key := range reservedHeaders {
+		if _, ok := o.headers[key]; ok {
+			return fmt.Errorf("%w: %s", ErrReservedHeader, key)
+		}
+	}
+	return nil
+}
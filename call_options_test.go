@@ -0,0 +1,90 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCallOptions_Headers(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	withHeaders := mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get(quidax.IdempotencyKeyHeader) == "key-1" &&
+			req.Header.Get("X-Trace") == "abc" &&
+			req.Header.Get("Authorization") == "Bearer token"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", withHeaders).Return(resp, nil).Once()
+
+	ctx := quidax.WithCallOptions(context.TODO(), quidax.WithHeader("X-Trace", "abc"))
+	ctx = quidax.WithCallOptions(ctx, quidax.WithIdempotencyKey("key-1"))
+
+	_, err := client.CreateWithdrawal(ctx, uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+}
+
+func TestWithCallOptions_ReservedHeaders(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	for _, key := range []string{"authorization", "Content-Type"} {
+		ctx := quidax.WithCallOptions(context.TODO(), quidax.WithHeader(key, "other"))
+
+		_, err := client.FetchParentAccount(ctx)
+		assert.ErrorIs(t, err, quidax.ErrReservedHeader)
+	}
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestWithCallOptions_Timeout(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	withDeadline := mock.MatchedBy(func(req *http.Request) bool {
+		deadline, ok := req.Context().Deadline()
+		return ok && time.Until(deadline) <= time.Second
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchBtcOk))}
+	mockHttpClient.On("Do", withDeadline).Return(resp, nil).Once()
+
+	ctx := quidax.WithCallOptions(context.TODO(), quidax.WithCallTimeout(time.Second))
+
+	_, err := client.FetchWallet(ctx, uuid.New(), "btc")
+	require.NoError(t, err)
+}
+
+func TestWithCallOptions_WithoutLogging(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithLogger(logger))
+
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchBtcOk))}, nil
+	}).Twice()
+
+	_, err := client.FetchWallet(quidax.WithCallOptions(context.TODO(), quidax.WithoutLogging()), uuid.New(), "btc")
+	require.NoError(t, err)
+	assert.Empty(t, hook.AllEntries())
+
+	_, err = client.FetchWallet(context.TODO(), uuid.New(), "btc")
+	require.NoError(t, err)
+	assert.Len(t, hook.AllEntries(), 2)
+}
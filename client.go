@@ -7,14 +7,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 const defaultBaseURL = "https://app.quidax.io/api"
 
+// DryRunMessage is the message of the synthetic responses returned in dry-run mode.
+const DryRunMessage = "dry run"
+
 type HttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -23,17 +29,29 @@ type Client interface {
 	AccountsClient
 	WalletsClient
 	WithdrawalsClient
+	DepositsClient
 	SwapClient
+	MarketsClient
+	ForUser(id uuid.UUID) UserClient
+	ForParent() UserClient
+	Do(ctx context.Context, method, path string, query url.Values, body, out interface{}, expectedStatuses ...int) error
 }
 
 var _ Client = (*client)(nil)
 
 type client struct {
-	httpClient HttpClient
-	logger     *logrus.Logger
-	baseURL    string
-	token      string
-	perPage    int
+	httpClient      HttpClient
+	logger          *logrus.Logger
+	baseURL         string
+	tokens          TokenProvider
+	perPage         int
+	dryRun          bool
+	auditSink       AuditSink
+	auditFailClosed bool
+	registry        *Registry
+	env             Environment
+	tokenEnv        Environment
+	guard           *withdrawalGuard
 }
 
 // ClientOption is a function that configures a Client.
@@ -60,11 +78,21 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithDryRun makes the client log mutating requests instead of sending them,
+// the response is synthesized from the request payload and has DryRunMessage as the message.
+// Payloads are still validated, against the default registry unless WithRegistry is set.
+// Currencies the default registry doesn't list are let through, only their amounts are checked.
+func WithDryRun() ClientOption {
+	return func(target *client) {
+		target.dryRun = true
+	}
+}
+
 func NewClient(token string, options ...ClientOption) *client {
 	c := &client{
 		httpClient: http.DefaultClient,
 		baseURL:    defaultBaseURL,
-		token:      token,
+		tokens:     StaticToken(token),
 		perPage:    100,
 	}
 
@@ -75,7 +103,26 @@ func NewClient(token string, options ...ClientOption) *client {
 	return c
 }
 
-func (c *client) newRequest(ctx context.Context, method, url string, body interface{}) (*request, error) {
+func (c *client) isDryRun() bool {
+	return c.dryRun
+}
+
+func (c *client) newRequest(ctx context.Context, method, url string, body interface{}) (r *request, err error) {
+	opts := callOptionsFromContext(ctx)
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var cancel context.CancelFunc
+	if opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer func() {
+			if err != nil {
+				cancel()
+			}
+		}()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -95,8 +142,9 @@ func (c *client) newRequest(ctx context.Context, method, url string, body interf
 		}
 	}
 
-	if c.logger != nil {
+	if c.logger != nil && !opts.skipLogging {
 		c.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"quidax.environment":        c.Environment(),
 			"http.request.method":       req.Method,
 			"http.request.url":          req.URL.String(),
 			"http.request.body.content": string(b),
@@ -104,48 +152,187 @@ func (c *client) newRequest(ctx context.Context, method, url string, body interf
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
-	return NewRequest(req), nil
+
+	for key, values := range opts.headers {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	r = NewRequest(req)
+	r.body = b
+	r.cancel = cancel
+	r.skipLogging = opts.skipLogging
+	return r, nil
 }
 
 func (c *client) do(ctx context.Context, req *request) error {
+	if req.cancel != nil {
+		defer req.cancel()
+	}
+
+	if err := c.checkEnvironment(req); err != nil {
+		return err
+	}
+
+	if c.dryRun && req.req.Method != http.MethodGet {
+		return c.dryRunDo(ctx, req)
+	}
+
+	// the token is only needed once the request is really sent, so dry runs work without one
+	if err := c.authorize(ctx, req); err != nil {
+		return err
+	}
+
+	resp, b, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if rotator, ok := c.tokens.(TokenRotator); ok && resp.StatusCode == http.StatusUnauthorized {
+		if rotated, err := c.rotate(ctx, rotator, req); err == nil && rotated {
+			resp, b, err = c.send(ctx, req)
+			if err != nil {
+				return err
+			}
+		} else if err != nil && c.logger != nil {
+			c.logger.WithContext(ctx).WithError(err).Warn("quidax.client -> token rotation failed")
+		}
+	}
+
+	if !slices.Contains(req.expectedStatuses, resp.StatusCode) {
+		return responseError(resp.StatusCode, b)
+	}
+
+	// the API reports some failures with a successful status code
+	var envelope struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(b, &envelope); err == nil && envelope.Status == "error" {
+		return responseError(resp.StatusCode, b)
+	}
+
+	if req.decodeTo != nil && len(b) > 0 {
+		if err := json.Unmarshal(b, req.decodeTo); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *client) authorize(ctx context.Context, req *request) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+
+	req.token = token
+	req.req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return nil
+}
+
+func (c *client) send(ctx context.Context, req *request) (*http.Response, []byte, error) {
 	resp, err := c.httpClient.Do(req.req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	resp.Body = io.NopCloser(bytes.NewBuffer(b))
-
-	if c.logger != nil {
+	if c.logger != nil && !req.skipLogging {
 		c.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"quidax.environment":         c.Environment(),
 			"http.response.status_code":  resp.StatusCode,
 			"http.response.body.content": string(b),
 			"http.response.headers":      resp.Header,
 		}).Debug("quidax.client -> response")
 	}
 
-	if !slices.Contains(req.expectedStatuses, resp.StatusCode) {
-		unexpectedResponse := UnexpectedResponse{Status: resp.StatusCode, Body: string(b)}
+	return resp, b, nil
+}
 
-		var errResponse ErrResponse
-		if err := json.Unmarshal(b, &errResponse); err != nil {
-			return unexpectedResponse
-		}
+// rotate replaces the rejected token of the request, so it can be sent again.
+func (c *client) rotate(ctx context.Context, rotator TokenRotator, req *request) (bool, error) {
+	token, err := rotator.Rotate(ctx, req.token)
+	if err != nil {
+		return false, err
+	}
 
-		return errResponse
+	if token == "" || token == req.token {
+		return false, nil
 	}
 
-	if req.decodeTo != nil {
-		if err := json.NewDecoder(resp.Body).Decode(req.decodeTo); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	req.token = token
+	req.req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	if req.body != nil {
+		req.req.Body = io.NopCloser(bytes.NewReader(req.body))
+	}
+
+	return true, nil
+}
+
+// dryRunQuoteTTL is how long synthetic quotes are valid for, like the ones of the API.
+const dryRunQuoteTTL = 15 * time.Second
+
+// dryRunData synthesizes the object the request would have created from its payload,
+// with the fields the API fills in set to plausible values.
+func dryRunData(req *request) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if len(req.body) > 0 {
+		if err := json.Unmarshal(req.body, &data); err != nil {
+			return nil, err
 		}
 	}
 
+	now := time.Now().UTC()
+	data["id"] = uuid.NewString()
+	data["created_at"] = now
+	data["updated_at"] = now
+
+	switch path := req.req.URL.Path; {
+	case strings.HasSuffix(path, "/swap_quotation"):
+		data["expires_at"] = now.Add(dryRunQuoteTTL)
+	case strings.HasSuffix(path, "/withdraws"):
+		data["status"] = WithdrawalStatusSubmitted
+	}
+
+	return data, nil
+}
+
+func (c *client) dryRunDo(ctx context.Context, req *request) error {
+	if c.logger != nil && !req.skipLogging {
+		c.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"quidax.environment":        c.Environment(),
+			"http.request.method":       req.req.Method,
+			"http.request.url":          req.req.URL.String(),
+			"http.request.body.content": string(req.body),
+		}).Info("quidax.client -> dry run")
+	}
+
+	if req.decodeTo == nil {
+		return nil
+	}
+
+	data, err := dryRunData(req)
+	if err != nil {
+		return fmt.Errorf("failed to build dry run response: %w", err)
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"status": "success", "message": DryRunMessage, "data": data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dry run response: %w", err)
+	}
+
+	if err := json.Unmarshal(b, req.decodeTo); err != nil {
+		return fmt.Errorf("failed to decode dry run response: %w", err)
+	}
+
 	return nil
 }
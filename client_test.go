@@ -0,0 +1,98 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDryRun_Mutating(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+
+	got, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"})
+	require.NoError(t, err)
+	assert.Equal(t, quidax.DryRunMessage, got.Message)
+	assert.Equal(t, "ref-123", got.Data.Reference)
+
+	account, err := client.CreateAccount(context.TODO(), quidax.CreateAccountPayload{Email: "john@doe.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "john@doe.com", account.Data.Email)
+
+	_, err = client.RequestWalletAddress(context.TODO(), uuid.New(), "btc", "")
+	require.NoError(t, err)
+
+	err = client.ConfirmQuote(context.TODO(), uuid.New(), uuid.New())
+	require.NoError(t, err)
+
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestWithDryRun_SynthesizesObjects(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+
+	quote, err := client.Quote(context.TODO(), uuid.New(), quidax.QuotePayload{FromCurrency: "usdt", ToCurrency: "btc", FromAmount: "10"})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, quote.Data.ID)
+	assert.Equal(t, "10", quote.Data.FromAmount)
+	assert.True(t, quote.Data.ExpiresAt.After(time.Now()))
+	assert.False(t, quote.Data.CreatedAt.IsZero())
+
+	withdrawal, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, withdrawal.Data.ID)
+	assert.Equal(t, quidax.WithdrawalStatusSubmitted, withdrawal.Data.Status)
+
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestWithDryRun_Validates(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.123456789"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	_, err = client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "doge", Amount: "1e-3"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	_, err = client.Quote(context.TODO(), uuid.New(), quidax.QuotePayload{FromCurrency: "usdt", ToCurrency: "xyz", FromAmount: "-10"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestWithDryRun_UnlistedCurrencies(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+
+	_, err := client.Quote(context.TODO(), uuid.New(), quidax.QuotePayload{FromCurrency: "usdt", ToCurrency: "doge", FromAmount: "10"})
+	assert.NoError(t, err)
+
+	_, err = client.RequestWalletAddress(context.TODO(), uuid.New(), "cngn", "")
+	assert.NoError(t, err)
+
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestWithDryRun_ReadsAreSent(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchMeOK))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchParentAccount(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "success", got.Status)
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+)
+
+var errUsage = errors.New("usage")
+
+type cli struct {
+	client quidax.Client
+	out    printer
+	stdin  io.Reader
+	stderr io.Writer
+}
+
+func (c *cli) dispatch(args []string) error {
+	ctx := context.Background()
+
+	switch args[0] {
+	case "accounts":
+		return c.accounts(ctx, args[1:])
+	case "wallets":
+		return c.wallets(ctx, args[1:])
+	case "fees":
+		return c.fees(ctx, args[1:])
+	case "withdraw":
+		return c.withdraw(ctx, args[1:])
+	case "quote":
+		return c.quote(ctx, args[1:])
+	case "confirm":
+		return c.confirm(ctx, args[1:])
+	}
+
+	return errUsage
+}
+
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parse parses interspersed flags, returning the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func (c *cli) user(arg string) (quidax.UserClient, error) {
+	if arg == quidax.ParentAccountID {
+		return c.client.ForParent(), nil
+	}
+
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user %q: %w", arg, err)
+	}
+
+	return c.client.ForUser(id), nil
+}
+
+func accountRows(accounts ...quidax.AccountData) [][]string {
+	rows := make([][]string, 0, len(accounts))
+	for _, a := range accounts {
+		rows = append(rows, []string{a.ID.String(), a.Email, a.FirstName, a.LastName, a.DisplayName})
+	}
+	return rows
+}
+
+var accountHeaders = []string{"ID", "EMAIL", "FIRST NAME", "LAST NAME", "DISPLAY NAME"}
+
+func (c *cli) accounts(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	fs := c.flagSet("accounts " + args[0])
+
+	switch args[0] {
+	case "list":
+		page := fs.Int("page", 1, "page number")
+		if _, err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+
+		resp, err := c.client.FetchAccounts(ctx, *page)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, accountHeaders, accountRows(resp.Data...))
+
+	case "get":
+		pos, err := parse(fs, args[1:])
+		if err != nil || len(pos) != 1 {
+			return errUsage
+		}
+
+		u, err := c.user(pos[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := u.FetchAccount(ctx)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, accountHeaders, accountRows(resp.Data))
+
+	case "create":
+		var payload quidax.CreateAccountPayload
+		fs.StringVar(&payload.Email, "email", "", "email")
+		fs.StringVar(&payload.FirstName, "first-name", "", "first name")
+		fs.StringVar(&payload.LastName, "last-name", "", "last name")
+		if _, err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+		if payload.Email == "" {
+			return errUsage
+		}
+
+		resp, err := c.client.CreateAccount(ctx, payload)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, accountHeaders, accountRows(resp.Data))
+
+	case "update":
+		var payload quidax.UpdateAccountPayload
+		fs.StringVar(&payload.FirstName, "first-name", "", "first name")
+		fs.StringVar(&payload.LastName, "last-name", "", "last name")
+		fs.StringVar(&payload.PhoneNumber, "phone", "", "phone number")
+		pos, err := parse(fs, args[1:])
+		if err != nil || len(pos) != 1 {
+			return errUsage
+		}
+
+		u, err := c.user(pos[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := u.UpdateAccount(ctx, payload)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, accountHeaders, accountRows(resp.Data))
+	}
+
+	return errUsage
+}
+
+func walletRows(wallets ...quidax.WalletData) [][]string {
+	rows := make([][]string, 0, len(wallets))
+	for _, w := range wallets {
+		rows = append(rows, []string{w.Name, w.Balance, w.Locked, w.Staked, w.DefaultNetwork})
+	}
+	return rows
+}
+
+func addressRows(addresses ...quidax.WalletAddressData) [][]string {
+	rows := make([][]string, 0, len(addresses))
+	for _, a := range addresses {
+		rows = append(rows, []string{a.Currency, a.Network, a.Address, a.DestinationTag})
+	}
+	return rows
+}
+
+var (
+	walletHeaders  = []string{"NAME", "BALANCE", "LOCKED", "STAKED", "NETWORK"}
+	addressHeaders = []string{"CURRENCY", "NETWORK", "ADDRESS", "TAG"}
+)
+
+func (c *cli) wallets(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	fs := c.flagSet("wallets " + args[0])
+	network := fs.String("network", "", "network")
+
+	pos, err := parse(fs, args[1:])
+	if err != nil || len(pos) == 0 {
+		return errUsage
+	}
+
+	u, err := c.user(pos[0])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "balance" && len(pos) == 1:
+		resp, err := u.FetchWallets(ctx)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, walletHeaders, walletRows(resp.Data...))
+
+	case args[0] == "balance" && len(pos) == 2:
+		resp, err := u.FetchWallet(ctx, pos[1])
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, walletHeaders, walletRows(resp.Data))
+
+	case args[0] == "addresses" && len(pos) == 2:
+		resp, err := u.FetchWalletAddresses(ctx, pos[1])
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, addressHeaders, addressRows(resp.Data...))
+
+	case args[0] == "new-address" && len(pos) == 2:
+		resp, err := u.RequestWalletAddress(ctx, pos[1], *network)
+		if err != nil {
+			return err
+		}
+		return c.out.print(resp.Data, addressHeaders, addressRows(resp.Data))
+	}
+
+	return errUsage
+}
+
+func (c *cli) fees(ctx context.Context, args []string) error {
+	fs := c.flagSet("fees")
+	network := fs.String("network", "", "network")
+
+	pos, err := parse(fs, args)
+	if err != nil || len(pos) != 1 {
+		return errUsage
+	}
+
+	resp, err := c.client.FetchWithdrawalFees(ctx, pos[0], *network)
+	if err != nil {
+		return err
+	}
+
+	fees := resp.GetFees()
+	rows := make([][]string, 0, len(fees))
+	for _, f := range fees {
+		rows = append(rows, []string{f.Type, formatFloat(f.Min), formatFloat(f.Max), formatFloat(f.Value)})
+	}
+	return c.out.print(fees, []string{"TYPE", "MIN", "MAX", "VALUE"}, rows)
+}
+
+func (c *cli) withdraw(ctx context.Context, args []string) error {
+	fs := c.flagSet("withdraw")
+
+	var payload quidax.CreateWithdrawalPayload
+	fs.StringVar(&payload.Currency, "currency", "", "currency")
+	fs.StringVar(&payload.Amount, "amount", "", "amount")
+	fs.StringVar(&payload.FundUID, "address", "", "destination address or user ID")
+	fs.StringVar(&payload.FundUID2, "memo", "", "destination tag or memo")
+	fs.StringVar(&payload.Network, "network", "", "network")
+	fs.StringVar(&payload.Reference, "reference", "", "reference")
+	fs.StringVar(&payload.TransactionNote, "note", "", "transaction note")
+	yes := fs.Bool("yes", false, "skip the confirmation prompt")
+
+	pos, err := parse(fs, args)
+	if err != nil || len(pos) != 1 || payload.Currency == "" || payload.Amount == "" || payload.FundUID == "" {
+		return errUsage
+	}
+
+	u, err := c.user(pos[0])
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Fprintf(c.stderr, "Withdraw %s %s to %s from %s? [y/N] ", payload.Amount, strings.ToUpper(payload.Currency), payload.FundUID, pos[0])
+
+		answer, _ := bufio.NewReader(c.stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errAborted
+		}
+	}
+
+	resp, err := u.CreateWithdrawal(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(resp.Data, []string{"ID", "REFERENCE", "STATUS"}, [][]string{{resp.Data.ID, resp.Data.Reference, resp.Data.Status}})
+}
+
+func quoteRows(q quidax.QuoteData) [][]string {
+	return [][]string{{q.ID.String(), q.FromAmount + " " + q.FromCurrency, q.ToAmount + " " + q.ToCurrency, strconv.FormatBool(q.Confirmed), q.ExpiresAt.String()}}
+}
+
+var quoteHeaders = []string{"ID", "FROM", "TO", "CONFIRMED", "EXPIRES AT"}
+
+func (c *cli) quote(ctx context.Context, args []string) error {
+	fs := c.flagSet("quote")
+
+	var payload quidax.QuotePayload
+	fs.StringVar(&payload.FromCurrency, "from", "", "currency to sell")
+	fs.StringVar(&payload.ToCurrency, "to", "", "currency to buy")
+	fs.StringVar(&payload.FromAmount, "from-amount", "", "amount to sell")
+	fs.StringVar(&payload.ToAmount, "to-amount", "", "amount to buy")
+
+	pos, err := parse(fs, args)
+	if err != nil || len(pos) != 1 || payload.FromCurrency == "" || payload.ToCurrency == "" || (payload.FromAmount == "") == (payload.ToAmount == "") {
+		return errUsage
+	}
+
+	u, err := c.user(pos[0])
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.Quote(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(resp.Data, quoteHeaders, quoteRows(resp.Data))
+}
+
+func (c *cli) confirm(ctx context.Context, args []string) error {
+	pos, err := parse(c.flagSet("confirm"), args)
+	if err != nil || len(pos) != 2 {
+		return errUsage
+	}
+
+	u, err := c.user(pos[0])
+	if err != nil {
+		return err
+	}
+
+	quoteID, err := uuid.Parse(pos[1])
+	if err != nil {
+		return fmt.Errorf("invalid quote ID %q: %w", pos[1], err)
+	}
+
+	if err := u.ConfirmQuote(ctx, quoteID); err != nil {
+		return err
+	}
+
+	return c.out.print(map[string]string{"id": quoteID.String(), "status": "confirmed"}, []string{"ID", "STATUS"}, [][]string{{quoteID.String(), "confirmed"}})
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
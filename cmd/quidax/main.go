@@ -0,0 +1,210 @@
+// Command quidax is a command-line tool for operating Quidax accounts.
+//
+// Usage:
+//
+//	quidax [-profile name] [-output table|json] <command> [arguments]
+//
+// The token is read from the QUIDAX_TOKEN environment variable,
+// or from the profile in the config file ($QUIDAX_CONFIG, defaults to <user config dir>/quidax/config.json):
+//
+//	{"profiles": {"default": {"token": "...", "base_url": "..."}}}
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitAPI
+	exitUnauthorized
+	exitNotFound
+	exitValidation
+	exitInsufficientFunds
+	exitAborted
+)
+
+var errAborted = errors.New("aborted")
+
+const usage = `Usage: quidax [-profile name] [-output table|json] <command> [arguments]
+
+Commands:
+  accounts list [-page n]
+  accounts get <user>
+  accounts create -email e -first-name f -last-name l
+  accounts update <user> [-first-name f] [-last-name l] [-phone p]
+  wallets balance <user> [currency]
+  wallets addresses <user> <currency>
+  wallets new-address <user> <currency> [-network n]
+  fees <currency> [-network n]
+  withdraw <user> -currency c -amount a -address addr [-network n] [-memo m] [-reference r] [-note n] [-yes]
+  quote <user> -from c -to c (-from-amount a | -to-amount a)
+  confirm <user> <quote-id>
+
+<user> is a sub-account ID or "me" for the parent account.
+`
+
+type profile struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"base_url"`
+}
+
+type config struct {
+	Profiles map[string]profile `json:"profiles"`
+}
+
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}))
+}
+
+func run(args []string, e env) int {
+	fs := flag.NewFlagSet("quidax", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() { fmt.Fprint(e.stderr, usage) }
+
+	profileName := fs.String("profile", "", "config profile to use")
+	output := fs.String("output", "table", "output format, table or json")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 || (*output != "table" && *output != "json") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	p, err := loadProfile(e.getenv, *profileName)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "quidax: %v\n", err)
+		return exitError
+	}
+
+	options := []quidax.ClientOption{}
+	if p.BaseURL != "" {
+		options = append(options, quidax.WithBaseURL(p.BaseURL))
+	}
+
+	cli := &cli{
+		client: quidax.NewClient(p.Token, options...),
+		out:    newPrinter(e.stdout, *output),
+		stdin:  e.stdin,
+		stderr: e.stderr,
+	}
+
+	if err := cli.dispatch(fs.Args()); err != nil {
+		if errors.Is(err, flag.ErrHelp) || errors.Is(err, errUsage) {
+			fs.Usage()
+			return exitUsage
+		}
+
+		fmt.Fprintf(e.stderr, "quidax: %v\n", err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func loadProfile(getenv func(string) string, name string) (profile, error) {
+	if name == "" {
+		name = getenv("QUIDAX_PROFILE")
+	}
+
+	if token := getenv("QUIDAX_TOKEN"); token != "" && name == "" {
+		return profile{Token: token, BaseURL: getenv("QUIDAX_BASE_URL")}, nil
+	}
+
+	if name == "" {
+		name = "default"
+	}
+
+	path := getenv("QUIDAX_CONFIG")
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return profile{}, fmt.Errorf("failed to locate config: %w", err)
+		}
+		path = filepath.Join(dir, "quidax", "config.json")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return profile{}, fmt.Errorf("no QUIDAX_TOKEN set and failed to read config: %w", err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return profile{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok || p.Token == "" {
+		return profile{}, fmt.Errorf("profile %q has no token in %s", name, path)
+	}
+
+	return p, nil
+}
+
+// errCodes maps the codes returned by the API to exit codes.
+var errCodes = map[string]int{
+	"unauthorized":         exitUnauthorized,
+	"forbidden":            exitUnauthorized,
+	"not_found":            exitNotFound,
+	"record_not_found":     exitNotFound,
+	"validation_error":     exitValidation,
+	"invalid_params":       exitValidation,
+	"insufficient_balance": exitInsufficientFunds,
+	"insufficient_funds":   exitInsufficientFunds,
+}
+
+var statusCodes = map[int]int{
+	http.StatusUnauthorized:        exitUnauthorized,
+	http.StatusForbidden:           exitUnauthorized,
+	http.StatusNotFound:            exitNotFound,
+	http.StatusUnprocessableEntity: exitValidation,
+}
+
+func exitCode(err error) int {
+	var errResponse quidax.ErrResponse
+	if errors.As(err, &errResponse) {
+		if code, ok := errCodes[errResponse.Data.Code]; ok {
+			return code
+		}
+		if code, ok := statusCodes[errResponse.StatusCode]; ok {
+			return code
+		}
+		return exitAPI
+	}
+
+	var unexpected quidax.UnexpectedResponse
+	if errors.As(err, &unexpected) {
+		if code, ok := statusCodes[unexpected.Status]; ok {
+			return code
+		}
+		return exitAPI
+	}
+
+	if errors.Is(err, errAborted) {
+		return exitAborted
+	}
+
+	return exitError
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEnv(stdin string, vars map[string]string) (env, *bytes.Buffer, *bytes.Buffer) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return env{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: stderr,
+		getenv: func(k string) string { return vars[k] },
+	}, stdout, stderr
+}
+
+func TestRun_Fees(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/fee", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"status":"success","message":"Successful","data":{"fee":1,"type":"flat"}}`))
+	}))
+	defer srv.Close()
+
+	e, stdout, _ := testEnv("", map[string]string{"QUIDAX_TOKEN": "token", "QUIDAX_BASE_URL": srv.URL})
+
+	code := run([]string{"fees", "usdt"}, e)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "flat")
+}
+
+func TestRun_WithdrawAborted(t *testing.T) {
+	e, _, _ := testEnv("n\n", map[string]string{"QUIDAX_TOKEN": "token", "QUIDAX_BASE_URL": "http://127.0.0.1:0"})
+
+	code := run([]string{"withdraw", "me", "-currency", "btc", "-amount", "0.1", "-address", "addr"}, e)
+	assert.Equal(t, exitAborted, code)
+}
+
+func TestRun_ErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":"error","message":"Not found","data":{"code":"not_found","message":"Not found"}}`))
+	}))
+	defer srv.Close()
+
+	e, _, stderr := testEnv("", map[string]string{"QUIDAX_TOKEN": "token", "QUIDAX_BASE_URL": srv.URL})
+
+	code := run([]string{"-output", "json", "accounts", "get", "me"}, e)
+	assert.Equal(t, exitNotFound, code)
+	assert.Contains(t, stderr.String(), "Not found")
+}
+
+func TestRun_ErrorCodeFallsBackToStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status":"error","message":"Token expired","data":{"code":"token_expired","message":"Token expired"}}`))
+	}))
+	defer srv.Close()
+
+	e, _, _ := testEnv("", map[string]string{"QUIDAX_TOKEN": "token", "QUIDAX_BASE_URL": srv.URL})
+
+	code := run([]string{"accounts", "get", "me"}, e)
+	assert.Equal(t, exitUnauthorized, code)
+}
+
+func TestRun_Usage(t *testing.T) {
+	e, _, _ := testEnv("", map[string]string{"QUIDAX_TOKEN": "token"})
+	assert.Equal(t, exitUsage, run([]string{"unknown"}, e))
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"profiles":{"default":{"token":"a"},"staging":{"token":"b","base_url":"https://example.com"}}}`), 0o600))
+
+	vars := map[string]string{"QUIDAX_CONFIG": path}
+	getenv := func(k string) string { return vars[k] }
+
+	p, err := loadProfile(getenv, "")
+	require.NoError(t, err)
+	assert.Equal(t, "a", p.Token)
+
+	p, err = loadProfile(getenv, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", p.BaseURL)
+
+	_, err = loadProfile(getenv, "missing")
+	assert.Error(t, err)
+}
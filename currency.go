@@ -0,0 +1,341 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Currency string
+
+func (c Currency) String() string {
+	return strings.ToLower(string(c))
+}
+
+const (
+	CurrencyBTC  Currency = "btc"
+	CurrencyETH  Currency = "eth"
+	CurrencyUSDT Currency = "usdt"
+	CurrencyUSDC Currency = "usdc"
+	CurrencyBNB  Currency = "bnb"
+	CurrencyXRP  Currency = "xrp"
+	CurrencyXLM  Currency = "xlm"
+	CurrencyTRX  Currency = "trx"
+	CurrencySOL  Currency = "sol"
+	CurrencyLTC  Currency = "ltc"
+	CurrencyNGN  Currency = "ngn"
+)
+
+type Network string
+
+func (n Network) String() string {
+	return strings.ToLower(string(n))
+}
+
+const (
+	NetworkBTC   Network = "btc"
+	NetworkERC20 Network = "erc20"
+	NetworkBEP20 Network = "bep20"
+	NetworkBEP2  Network = "bep2"
+	NetworkTRC20 Network = "trc20"
+	NetworkSOL   Network = "sol"
+	NetworkXRP   Network = "xrp"
+	NetworkXLM   Network = "xlm"
+	NetworkLTC   Network = "ltc"
+)
+
+type CurrencyInfo struct {
+	Code           Currency
+	Precision      int
+	IsCrypto       bool
+	DefaultNetwork Network
+	Networks       []Network
+	// MemoNetworks are the networks where exchanges tell their customers apart by destination tag or memo.
+	MemoNetworks []Network
+}
+
+func (i CurrencyInfo) SupportsNetwork(n string) bool {
+	return len(i.Networks) == 0 || slices.Contains(i.Networks, Network(strings.ToLower(n)))
+}
+
+// RequiresMemo reports whether withdrawals on the network need a memo, an empty network means the default one.
+func (i CurrencyInfo) RequiresMemo(n string) bool {
+	if n == "" {
+		n = string(i.DefaultNetwork)
+	}
+	return slices.Contains(i.MemoNetworks, Network(strings.ToLower(n)))
+}
+
+// MemoRequiredError is returned when a currency that requires a memo is sent to an exchange without one.
+type MemoRequiredError struct {
+	Currency string
+	Address  string
+	Exchange string
+}
+
+func (e MemoRequiredError) Error() string {
+	return fmt.Sprintf("A memo is required to send %s to %s address %s", e.Currency, e.Exchange, e.Address)
+}
+
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// Registry describes the currencies and networks payloads are validated against.
+type Registry struct {
+	mu         sync.RWMutex
+	currencies map[Currency]CurrencyInfo
+	exchanges  map[string]string
+	// lenient registries accept unknown currencies, only the format of their amounts is checked.
+	lenient bool
+}
+
+// maxPrecision is the number of decimals allowed for currencies a lenient registry doesn't know.
+const maxPrecision = 18
+
+func NewRegistry(currencies ...CurrencyInfo) *Registry {
+	r := &Registry{currencies: make(map[Currency]CurrencyInfo), exchanges: make(map[string]string)}
+	for _, info := range currencies {
+		r.Set(info)
+	}
+	return r
+}
+
+// defaultRegistry is the registry of clients without WithRegistry, it is never refreshed.
+var defaultRegistry = sync.OnceValue(DefaultRegistry)
+
+// dryRunRegistry validates the payloads of dry runs without WithRegistry. It is lenient,
+// so currencies the default registry doesn't list are not rejected when the API would accept them.
+var dryRunRegistry = sync.OnceValue(func() *Registry {
+	r := DefaultRegistry()
+	r.lenient = true
+	return r
+})
+
+// DefaultRegistry returns a registry with the commonly used currencies, call Refresh to sync it with the API.
+func DefaultRegistry() *Registry {
+	return NewRegistry(
+		CurrencyInfo{Code: CurrencyBTC, Precision: 8, IsCrypto: true, DefaultNetwork: NetworkBTC, Networks: []Network{NetworkBTC, NetworkBEP20}},
+		CurrencyInfo{Code: CurrencyETH, Precision: 8, IsCrypto: true, DefaultNetwork: NetworkERC20, Networks: []Network{NetworkERC20, NetworkBEP20}},
+		CurrencyInfo{Code: CurrencyUSDT, Precision: 6, IsCrypto: true, DefaultNetwork: NetworkTRC20, Networks: []Network{NetworkTRC20, NetworkERC20, NetworkBEP20, NetworkSOL}},
+		CurrencyInfo{Code: CurrencyUSDC, Precision: 6, IsCrypto: true, DefaultNetwork: NetworkERC20, Networks: []Network{NetworkERC20, NetworkBEP20, NetworkSOL}},
+		CurrencyInfo{Code: CurrencyBNB, Precision: 8, IsCrypto: true, DefaultNetwork: NetworkBEP20, Networks: []Network{NetworkBEP20, NetworkBEP2}, MemoNetworks: []Network{NetworkBEP2}},
+		CurrencyInfo{Code: CurrencyXRP, Precision: 6, IsCrypto: true, DefaultNetwork: NetworkXRP, Networks: []Network{NetworkXRP}, MemoNetworks: []Network{NetworkXRP}},
+		CurrencyInfo{Code: CurrencyXLM, Precision: 7, IsCrypto: true, DefaultNetwork: NetworkXLM, Networks: []Network{NetworkXLM}, MemoNetworks: []Network{NetworkXLM}},
+		CurrencyInfo{Code: CurrencyTRX, Precision: 6, IsCrypto: true, DefaultNetwork: NetworkTRC20, Networks: []Network{NetworkTRC20}},
+		CurrencyInfo{Code: CurrencySOL, Precision: 8, IsCrypto: true, DefaultNetwork: NetworkSOL, Networks: []Network{NetworkSOL}},
+		CurrencyInfo{Code: CurrencyLTC, Precision: 8, IsCrypto: true, DefaultNetwork: NetworkLTC, Networks: []Network{NetworkLTC}},
+		CurrencyInfo{Code: CurrencyNGN, Precision: 2, IsCrypto: false},
+	)
+}
+
+func (r *Registry) Set(info CurrencyInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info.Code = Currency(info.Code.String())
+	r.currencies[info.Code] = info
+}
+
+// AddExchangeAddresses marks the addresses as belonging to the exchange,
+// withdrawals to them need a memo when the network of the currency requires one.
+func (r *Registry) AddExchangeAddresses(exchange string, addresses ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, address := range addresses {
+		r.exchanges[address] = exchange
+	}
+}
+
+func (r *Registry) exchangeFor(address string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	exchange, ok := r.exchanges[address]
+	return exchange, ok
+}
+
+func (r *Registry) Lookup(currency string) (CurrencyInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.currencies[Currency(strings.ToLower(currency))]
+	return info, ok
+}
+
+// Refresh updates the registry with the currencies and networks of the wallets, e.g. of client.ForParent().
+// Precision and memo requirements are not returned by the API and are kept as they are.
+func (r *Registry) Refresh(ctx context.Context, client UserClient) error {
+	wallets, err := client.FetchWallets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+
+	for _, w := range wallets.Data {
+		info, ok := r.Lookup(w.Currency)
+		if !ok {
+			info = CurrencyInfo{Code: Currency(w.Currency), Precision: 2}
+			if w.IsCrypto {
+				info.Precision = 8
+			}
+		}
+
+		info.IsCrypto = w.IsCrypto
+		info.DefaultNetwork = Network(strings.ToLower(w.DefaultNetwork))
+		info.Networks = info.Networks[:0:0]
+		for _, n := range w.Networks {
+			info.Networks = append(info.Networks, Network(strings.ToLower(n.ID)))
+		}
+
+		r.Set(info)
+	}
+
+	return nil
+}
+
+func (r *Registry) ValidateCurrency(currency string) (CurrencyInfo, error) {
+	info, ok := r.Lookup(currency)
+	if !ok && r.lenient {
+		return CurrencyInfo{Code: Currency(strings.ToLower(currency)), Precision: maxPrecision}, nil
+	}
+	if !ok {
+		return info, ValidationError{Field: "currency", Value: currency, Reason: "unknown currency"}
+	}
+	return info, nil
+}
+
+// ValidateNetwork checks the network is supported by the currency, an empty network means the default one.
+func (r *Registry) ValidateNetwork(currency, network string) error {
+	info, err := r.ValidateCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	if network != "" && !info.SupportsNetwork(network) {
+		return ValidationError{Field: "network", Value: network, Reason: fmt.Sprintf("not supported for %s", info.Code)}
+	}
+
+	return nil
+}
+
+// decimalPattern matches plain decimal numbers, the API does not accept exponents, signs or special values.
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ValidateAmount checks the amount is positive and has no more decimals than the currency supports.
+func (r *Registry) ValidateAmount(currency, amount string) error {
+	info, err := r.ValidateCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	f, err := strconv.ParseFloat(amount, 64)
+	if err != nil || f <= 0 || !decimalPattern.MatchString(amount) {
+		return ValidationError{Field: "amount", Value: amount, Reason: "must be a positive decimal number"}
+	}
+
+	if _, decimals, ok := strings.Cut(amount, "."); ok && len(strings.TrimRight(decimals, "0")) > info.Precision {
+		return ValidationError{Field: "amount", Value: amount, Reason: fmt.Sprintf("%s supports %d decimals", info.Code, info.Precision)}
+	}
+
+	return nil
+}
+
+func (r *Registry) ValidateQuote(payload QuotePayload) error {
+	if _, err := r.ValidateCurrency(payload.FromCurrency); err != nil {
+		return err
+	}
+
+	if _, err := r.ValidateCurrency(payload.ToCurrency); err != nil {
+		return err
+	}
+
+	if payload.FromAmount != "" {
+		return r.ValidateAmount(payload.FromCurrency, payload.FromAmount)
+	}
+
+	if payload.ToAmount != "" {
+		return r.ValidateAmount(payload.ToCurrency, payload.ToAmount)
+	}
+
+	return ValidationError{Field: "amount", Reason: "either from_amount or to_amount is required"}
+}
+
+// ValidateMemo checks a memo is set when a currency is sent on a network that requires one to a known exchange address.
+// Only addresses added with AddExchangeAddresses are checked, and the client only does so when it is created WithRegistry.
+func (r *Registry) ValidateMemo(payload CreateWithdrawalPayload) error {
+	info, err := r.ValidateCurrency(payload.Currency)
+	if err != nil {
+		return err
+	}
+
+	if !info.RequiresMemo(payload.Network) || strings.TrimSpace(payload.FundUID2) != "" {
+		return nil
+	}
+
+	if exchange, ok := r.exchangeFor(payload.FundUID); ok {
+		return MemoRequiredError{Currency: info.Code.String(), Address: payload.FundUID, Exchange: exchange}
+	}
+
+	return nil
+}
+
+func (r *Registry) ValidateWithdrawal(payload CreateWithdrawalPayload) error {
+	if err := r.ValidateNetwork(payload.Currency, payload.Network); err != nil {
+		return err
+	}
+
+	if err := r.ValidateAmount(payload.Currency, payload.Amount); err != nil {
+		return err
+	}
+
+	return r.ValidateMemo(payload)
+}
+
+// precision returns the decimals of the currency from the configured registry, or from the default one.
+func (c *client) precision(currency string) int {
+	r := c.registry
+	if r == nil {
+		r = defaultRegistry()
+	}
+
+	if info, ok := r.Lookup(currency); ok {
+		return info.Precision
+	}
+
+	return 8
+}
+
+// precisionOf returns the decimals of the currency for clients created by NewClient, or from the default registry.
+func precisionOf(c interface{}, currency string) int {
+	if p, ok := c.(interface{ precision(string) int }); ok {
+		return p.precision(currency)
+	}
+
+	if info, ok := defaultRegistry().Lookup(currency); ok {
+		return info.Precision
+	}
+
+	return 8
+}
+
+// validator returns the registry payloads are validated against, dry runs fall back to a lenient default one.
+func (c *client) validator() *Registry {
+	if c.registry == nil && c.dryRun {
+		return dryRunRegistry()
+	}
+	return c.registry
+}
+
+// WithRegistry makes the client validate quotes, withdrawals and address requests before sending them.
+func WithRegistry(r *Registry) ClientOption {
+	return func(target *client) {
+		target.registry = r
+	}
+}
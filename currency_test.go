@@ -0,0 +1,140 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ValidateAmount(t *testing.T) {
+	r := quidax.DefaultRegistry()
+
+	assert.NoError(t, r.ValidateAmount("BTC", "0.00000001"))
+	assert.NoError(t, r.ValidateAmount("ngn", "100.50"))
+
+	var verr quidax.ValidationError
+	require.ErrorAs(t, r.ValidateAmount("ngn", "100.505"), &verr)
+	assert.Equal(t, "amount", verr.Field)
+
+	require.ErrorAs(t, r.ValidateAmount("btc", "-1"), &verr)
+	require.ErrorAs(t, r.ValidateAmount("btc", "1e-3"), &verr)
+	require.ErrorAs(t, r.ValidateAmount("btc", "+1"), &verr)
+	require.ErrorAs(t, r.ValidateAmount("btc", "Inf"), &verr)
+	require.ErrorAs(t, r.ValidateAmount("btc", "0x1p-2"), &verr)
+	require.ErrorAs(t, r.ValidateAmount("doge", "1"), &verr)
+	assert.Equal(t, "currency", verr.Field)
+}
+
+func TestRegistry_ValidateNetwork(t *testing.T) {
+	r := quidax.DefaultRegistry()
+
+	assert.NoError(t, r.ValidateNetwork("usdt", "trc20"))
+	assert.NoError(t, r.ValidateNetwork("usdt", ""))
+
+	var verr quidax.ValidationError
+	require.ErrorAs(t, r.ValidateNetwork("btc", "trc20"), &verr)
+	assert.Equal(t, "network", verr.Field)
+}
+
+func TestRegistry_Refresh(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	r := quidax.NewRegistry()
+	require.NoError(t, r.Refresh(context.TODO(), client.ForParent()))
+
+	info, ok := r.Lookup("btc")
+	require.True(t, ok)
+	assert.True(t, info.IsCrypto)
+	assert.Equal(t, 8, info.Precision)
+	assert.Equal(t, quidax.NetworkBTC, info.DefaultNetwork)
+	assert.Equal(t, []quidax.Network{quidax.NetworkBTC, quidax.NetworkBEP20}, info.Networks)
+}
+
+func TestRegistry_RefreshNormalizesNetworks(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	body := bytes.ReplaceAll(walletsFetchAllOk, []byte(`"default_network": "btc"`), []byte(`"default_network": "BTC"`))
+	body = bytes.ReplaceAll(body, []byte(`"id": "bep20"`), []byte(`"id": "BEP20"`))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	r := quidax.NewRegistry()
+	require.NoError(t, r.Refresh(context.TODO(), client.ForParent()))
+
+	info, ok := r.Lookup("btc")
+	require.True(t, ok)
+	assert.Equal(t, quidax.NetworkBTC, info.DefaultNetwork)
+	assert.Equal(t, []quidax.Network{quidax.NetworkBTC, quidax.NetworkBEP20}, info.Networks)
+}
+
+func TestWithRegistry_RejectsBeforeSending(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithRegistry(quidax.DefaultRegistry()))
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Network: "trc20", Amount: "0.1"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	_, err = client.Quote(context.TODO(), uuid.New(), quidax.QuotePayload{FromCurrency: "btc", ToCurrency: "ngn", FromAmount: "0.123456789"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	_, err = client.RequestWalletAddress(context.TODO(), uuid.New(), "xyz", "")
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestRegistry_ValidateMemo(t *testing.T) {
+	r := quidax.DefaultRegistry()
+	r.AddExchangeAddresses("Binance", "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh")
+
+	payload := quidax.CreateWithdrawalPayload{Currency: "xrp", Amount: "10", FundUID: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"}
+
+	var merr quidax.MemoRequiredError
+	require.ErrorAs(t, r.ValidateWithdrawal(payload), &merr)
+	assert.Equal(t, "Binance", merr.Exchange)
+	assert.Equal(t, "xrp", merr.Currency)
+
+	payload.FundUID2 = "104872"
+	assert.NoError(t, r.ValidateWithdrawal(payload))
+
+	payload = quidax.CreateWithdrawalPayload{Currency: "xrp", Amount: "10", FundUID: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"}
+	assert.NoError(t, r.ValidateWithdrawal(payload), "unknown addresses are not checked")
+
+	r.AddExchangeAddresses("Binance", "0x28C6c06298d514Db089934071355E5743bf21d60", "bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23")
+
+	payload = quidax.CreateWithdrawalPayload{Currency: "bnb", Network: "bep20", Amount: "1", FundUID: "0x28C6c06298d514Db089934071355E5743bf21d60"}
+	assert.NoError(t, r.ValidateWithdrawal(payload), "BEP20 does not use memos")
+
+	payload = quidax.CreateWithdrawalPayload{Currency: "bnb", Network: "bep2", Amount: "1", FundUID: "bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23"}
+	assert.ErrorAs(t, r.ValidateWithdrawal(payload), &merr)
+}
+
+func TestCreateWithdrawal_SendsMemo(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	withMemo := mock.MatchedBy(func(req *http.Request) bool {
+		b, _ := io.ReadAll(req.Body)
+		return bytes.Contains(b, []byte(`"fund_uid2":"1234"`))
+	})
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", withMemo).Return(resp, nil).Once()
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "xlm", Amount: "5", FundUID: "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", FundUID2: "1234"})
+	require.NoError(t, err)
+}
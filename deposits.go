@@ -0,0 +1,58 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DepositsClient interface {
+	FetchDeposits(ctx context.Context, userID uuid.UUID, currency, state string, page int) (DepositsResponse, error)
+}
+
+type DepositData struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Currency  string    `json:"currency"`
+	Amount    string    `json:"amount"`
+	Fee       string    `json:"fee"`
+	TxID      string    `json:"txid"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	DoneAt    time.Time `json:"done_at"`
+}
+
+func (d DepositData) GetAmount() float64 {
+	f, _ := strconv.ParseFloat(d.Amount, 64)
+	return f
+}
+
+type DepositsResponse = Response[[]DepositData]
+
+// FetchDeposits returns a page of the deposits of the user, currency and state are optional filters.
+func (c *client) FetchDeposits(ctx context.Context, userID uuid.UUID, currency, state string, page int) (data DepositsResponse, err error) {
+	return c.fetchDeposits(ctx, userID.String(), currency, state, page)
+}
+
+func (c *client) fetchDeposits(ctx context.Context, user, currency, state string, page int) (data DepositsResponse, err error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	query.Set("page", strconv.Itoa(page))
+
+	if currency != "" {
+		query.Set("currency", strings.ToLower(currency))
+	}
+
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	return call[[]DepositData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/deposits", user), query, nil, http.StatusOK)
+}
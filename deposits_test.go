@@ -0,0 +1,29 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchDeposits_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(depositsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchDeposits(context.TODO(), uuid.New(), "btc", "", 1)
+	require.NoError(t, err)
+	require.Len(t, got.Data, 1)
+	assert.Equal(t, 0.5, got.Data[0].GetAmount())
+	assert.Equal(t, "tx-dep", got.Data[0].TxID)
+}
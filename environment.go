@@ -0,0 +1,77 @@
+package quidax
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const sandboxBaseURL = "https://sandbox.quidax.io/api"
+
+type Environment string
+
+const (
+	EnvironmentProduction Environment = "production"
+	EnvironmentSandbox    Environment = "sandbox"
+	// EnvironmentCustom is any base URL set with WithBaseURL, e.g. a proxy or a mock server.
+	EnvironmentCustom Environment = "custom"
+)
+
+var ErrEnvironmentMismatch = errors.New("token does not belong to the environment")
+
+// EnvironmentReporter is implemented by the clients returned by NewClient.
+type EnvironmentReporter interface {
+	Environment() Environment
+}
+
+// WithEnvironment sets the environment and its base URL, EnvironmentCustom keeps the base URL set with WithBaseURL.
+// A base URL set after it, e.g. of a proxy, keeps the environment, so the token environment is still checked against it.
+func WithEnvironment(env Environment) ClientOption {
+	return func(target *client) {
+		target.env = env
+		switch env {
+		case EnvironmentProduction:
+			target.baseURL = defaultBaseURL
+		case EnvironmentSandbox:
+			target.baseURL = sandboxBaseURL
+		}
+	}
+}
+
+// WithTokenEnvironment declares the environment the token was issued for.
+// Mutating requests are refused with ErrEnvironmentMismatch when the client is configured for another one,
+// e.g. a sandbox token against production. Custom environments are never refused.
+func WithTokenEnvironment(env Environment) ClientOption {
+	return func(target *client) {
+		target.tokenEnv = env
+	}
+}
+
+// Environment returns the environment the client sends requests to, the one set WithEnvironment,
+// or else the one of the base URL.
+func (c *client) Environment() Environment {
+	if c.env != "" {
+		return c.env
+	}
+
+	switch c.baseURL {
+	case defaultBaseURL:
+		return EnvironmentProduction
+	case sandboxBaseURL:
+		return EnvironmentSandbox
+	}
+	return EnvironmentCustom
+}
+
+// checkEnvironment refuses mutating requests sent with a token of another environment.
+func (c *client) checkEnvironment(req *request) error {
+	if req.req.Method == http.MethodGet || c.tokenEnv == "" || c.tokenEnv == EnvironmentCustom {
+		return nil
+	}
+
+	if env := c.Environment(); env != EnvironmentCustom && env != c.tokenEnv {
+		return fmt.Errorf("%w: %s token used against %s", ErrEnvironmentMismatch, c.tokenEnv, env)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package quidax_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment(t *testing.T) {
+	assert.Equal(t, quidax.EnvironmentProduction, quidax.NewClient("token").Environment())
+	assert.Equal(t, quidax.EnvironmentSandbox, quidax.NewClient("token", quidax.WithEnvironment(quidax.EnvironmentSandbox)).Environment())
+	assert.Equal(t, quidax.EnvironmentCustom, quidax.NewClient("token", quidax.WithBaseURL("https://example.com")).Environment())
+	assert.Equal(t, quidax.EnvironmentCustom, quidax.NewClient("token", quidax.WithBaseURL("https://example.com"), quidax.WithEnvironment(quidax.EnvironmentCustom)).Environment())
+	assert.Equal(t, quidax.EnvironmentProduction, quidax.NewClient("token", quidax.WithEnvironment(quidax.EnvironmentProduction), quidax.WithBaseURL("https://proxy.example.com")).Environment())
+}
+
+func TestWithTokenEnvironment_RefusesMismatchThroughProxy(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient),
+		quidax.WithEnvironment(quidax.EnvironmentProduction), quidax.WithBaseURL("https://proxy.example.com"), quidax.WithTokenEnvironment(quidax.EnvironmentSandbox))
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	assert.ErrorIs(t, err, quidax.ErrEnvironmentMismatch)
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestWithTokenEnvironment_RefusesMismatch(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithTokenEnvironment(quidax.EnvironmentSandbox))
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	assert.ErrorIs(t, err, quidax.ErrEnvironmentMismatch)
+
+	err = client.ConfirmQuote(context.TODO(), uuid.New(), uuid.New())
+	assert.ErrorIs(t, err, quidax.ErrEnvironmentMismatch)
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestWithTokenEnvironment_AllowsMatch(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun(),
+		quidax.WithEnvironment(quidax.EnvironmentSandbox), quidax.WithTokenEnvironment(quidax.EnvironmentSandbox))
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+
+	production := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun(), quidax.WithTokenEnvironment(quidax.EnvironmentProduction))
+	_, err = production.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+}
@@ -1,6 +1,10 @@
 package quidax
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
 
 type UnexpectedResponse struct {
 	Status int
@@ -12,9 +16,10 @@ func (r UnexpectedResponse) Error() string {
 }
 
 type ErrResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Data    struct {
+	StatusCode int    `json:"-"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Data       struct {
 		Code    string `json:"code"`
 		Message string `json:"message"`
 	} `json:"data"`
@@ -23,3 +28,29 @@ type ErrResponse struct {
 func (e ErrResponse) Error() string {
 	return fmt.Sprintf("Error during API call. Status: %s Message: %s", e.Status, e.Message)
 }
+
+// responseError builds the error of a failed API response.
+func responseError(status int, body []byte) error {
+	var errResponse ErrResponse
+	if err := json.Unmarshal(body, &errResponse); err != nil {
+		return UnexpectedResponse{Status: status, Body: string(body)}
+	}
+
+	errResponse.StatusCode = status
+	return errResponse
+}
+
+// responseStatus returns the HTTP status code of the API response err was built from.
+func responseStatus(err error) (int, bool) {
+	var errResponse ErrResponse
+	if errors.As(err, &errResponse) {
+		return errResponse.StatusCode, true
+	}
+
+	var unexpected UnexpectedResponse
+	if errors.As(err, &unexpected) {
+		return unexpected.Status, true
+	}
+
+	return 0, false
+}
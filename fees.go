@@ -0,0 +1,107 @@
+package quidax
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	FeeTypeFlat       = "flat"
+	FeeTypePercentage = "percentage"
+)
+
+var ErrNoFeeTier = errors.New("no fee tier matches the amount")
+
+// FeeSchedule is a set of fee tiers as returned by FetchWithdrawalFees.
+// A tier applies to amounts in the [Min, Max) range, a zero Max means the tier is unbounded.
+// Flat tiers charge Value, percentage tiers charge Value percent of the amount.
+// The fee is charged on top of the withdrawn amount.
+type FeeSchedule []Fee
+
+func (r FeesResponse) GetFeeSchedule() FeeSchedule {
+	return FeeSchedule(r.GetFees())
+}
+
+func (f Fee) contains(amount float64) bool {
+	return amount >= f.Min && (f.Max == 0 || amount < f.Max)
+}
+
+func (f Fee) feeFor(amount float64) float64 {
+	if f.Type == FeeTypePercentage {
+		return amount * f.Value / 100
+	}
+	return f.Value
+}
+
+func (s FeeSchedule) tierFor(amount float64) (Fee, error) {
+	for _, f := range s {
+		if f.contains(amount) {
+			return f, nil
+		}
+	}
+	return Fee{}, ErrNoFeeTier
+}
+
+// FeeFor returns the fee charged for withdrawing the amount.
+func (s FeeSchedule) FeeFor(amount float64) (float64, error) {
+	tier, err := s.tierFor(amount)
+	if err != nil {
+		return 0, err
+	}
+	return tier.feeFor(amount), nil
+}
+
+// GrossFor returns the total amount debited from the wallet so the recipient receives exactly net.
+func (s FeeSchedule) GrossFor(net float64) (float64, error) {
+	fee, err := s.FeeFor(net)
+	if err != nil {
+		return 0, err
+	}
+	return net + fee, nil
+}
+
+// NetFor returns the largest amount, truncated to precision decimals, that can be withdrawn when
+// available is in the wallet, so the amount plus its fee doesn't exceed available.
+// Zero is returned when available doesn't cover the fee.
+func (s FeeSchedule) NetFor(available float64, precision int) float64 {
+	unit := math.Pow10(-precision)
+
+	if len(s) == 0 {
+		return truncate(available, precision)
+	}
+
+	best := 0.0
+
+	for _, f := range s {
+		net := available - f.Value
+		if f.Type == FeeTypePercentage {
+			net = available / (1 + f.Value/100)
+		}
+
+		net = truncate(net, precision)
+		if f.Max != 0 && net >= f.Max {
+			net = truncate(f.Max-unit, precision)
+		}
+
+		if net < f.Min || net <= best {
+			continue
+		}
+
+		// truncation only ever lowers the amount, this guards against rounding of the fee itself
+		for net > 0 && net+f.feeFor(net) > available+unit/2 {
+			net = truncate(net-unit, precision)
+		}
+
+		if net >= f.Min && net > best {
+			best = net
+		}
+	}
+
+	return best
+}
+
+// truncate rounds the amount down to precision decimals, ignoring float noise below the last decimal.
+func truncate(amount float64, precision int) float64 {
+	scale := math.Pow10(precision)
+	return math.Floor(amount*scale+1e-6) / scale
+}
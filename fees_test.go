@@ -0,0 +1,96 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeSchedule_FeeFor(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(feesMulti))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchWithdrawalFees(context.TODO(), "btc", "")
+	require.NoError(t, err)
+
+	schedule := got.GetFeeSchedule()
+
+	fee, err := schedule.FeeFor(0.001)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0002, fee)
+
+	fee, err = schedule.FeeFor(0.0136)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0003, fee)
+
+	fee, err = schedule.FeeFor(5)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0005, fee)
+
+	_, err = schedule.FeeFor(11)
+	assert.ErrorIs(t, err, quidax.ErrNoFeeTier)
+}
+
+func TestFeeSchedule_FeeFor_Unbounded(t *testing.T) {
+	schedule := quidax.FeeSchedule{{Type: quidax.FeeTypeFlat, Value: 1}}
+
+	fee, err := schedule.FeeFor(1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, fee)
+}
+
+func TestFeeSchedule_FeeFor_Percentage(t *testing.T) {
+	schedule := quidax.FeeSchedule{{Min: 0, Max: 100, Type: quidax.FeeTypePercentage, Value: 1.5}}
+
+	fee, err := schedule.FeeFor(50)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, fee, 1e-9)
+}
+
+func TestFeeSchedule_GrossFor(t *testing.T) {
+	schedule := quidax.FeeSchedule{
+		{Min: 0, Max: 10, Type: quidax.FeeTypeFlat, Value: 0.5},
+		{Min: 10, Max: 0, Type: quidax.FeeTypePercentage, Value: 2},
+	}
+
+	gross, err := schedule.GrossFor(5)
+	require.NoError(t, err)
+	assert.Equal(t, 5.5, gross)
+
+	gross, err = schedule.GrossFor(100)
+	require.NoError(t, err)
+	assert.InDelta(t, 102, gross, 1e-9)
+}
+
+func TestFeeSchedule_NetFor(t *testing.T) {
+	schedule := quidax.FeeSchedule{
+		{Min: 0, Max: 10, Type: quidax.FeeTypeFlat, Value: 0.5},
+		{Min: 10, Max: 0, Type: quidax.FeeTypePercentage, Value: 2},
+	}
+
+	assert.Equal(t, 4.5, schedule.NetFor(5, 8))
+	assert.Equal(t, 100.0, schedule.NetFor(102, 8))
+	assert.Equal(t, 10.0, schedule.NetFor(10.2, 2))
+	assert.Equal(t, 0.0, schedule.NetFor(0.4, 8))
+	assert.Equal(t, 1.23456789, quidax.FeeSchedule{}.NetFor(1.234567891, 8))
+}
+
+func TestFeeSchedule_NetFor_TierBoundary(t *testing.T) {
+	schedule := quidax.FeeSchedule{
+		{Min: 0, Max: 5, Type: quidax.FeeTypeFlat, Value: 0.1},
+		{Min: 5, Max: 0, Type: quidax.FeeTypeFlat, Value: 3},
+	}
+
+	assert.Equal(t, 4.99999999, schedule.NetFor(5.2, 8))
+	assert.Equal(t, 6.0, schedule.NetFor(9, 8))
+}
@@ -0,0 +1,263 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// TenantConfig configures the client of a single Quidax parent account.
+type TenantConfig struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"base_url"`
+	// Environment is the environment the client sends requests to, also when BaseURL points to a proxy of it.
+	Environment Environment `json:"environment"`
+	// TokenEnvironment is the environment the token was issued for, mutating requests to another one are refused.
+	TokenEnvironment Environment `json:"token_environment"`
+	// RequestsPerSecond limits the requests sent with the token, zero uses the default of the manager.
+	RequestsPerSecond float64 `json:"requests_per_second"`
+}
+
+type TenantHealth struct {
+	Tenant    string        `json:"tenant"`
+	Healthy   bool          `json:"healthy"`
+	Error     string        `json:"error,omitempty"`
+	Latency   time.Duration `json:"latency"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+type HealthReport struct {
+	Tenants []TenantHealth `json:"tenants"`
+}
+
+// Healthy reports whether every tenant is healthy.
+func (r HealthReport) Healthy() bool {
+	for _, t := range r.Tenants {
+		if !t.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Manager holds the clients of several parent accounts, one per tenant.
+// The clients share a single HTTP client, requests are rate limited per token.
+type Manager struct {
+	httpClient HttpClient
+	options    []ClientOption
+	rps        float64
+	clients    map[string]Client
+}
+
+// ManagerOption is a function that configures a Manager.
+type ManagerOption func(*Manager)
+
+// WithManagerHTTPClient sets the HTTP client shared by the clients.
+func WithManagerHTTPClient(c HttpClient) ManagerOption {
+	return func(target *Manager) {
+		target.httpClient = c
+	}
+}
+
+// WithManagerClientOptions sets the options every client is built with, e.g. WithLogger.
+func WithManagerClientOptions(options ...ClientOption) ManagerOption {
+	return func(target *Manager) {
+		target.options = append(target.options, options...)
+	}
+}
+
+// WithDefaultRateLimit sets the requests per second of tenants that don't set their own, zero disables the limit.
+// It defaults to DefaultRequestsPerSecond.
+func WithDefaultRateLimit(rps float64) ManagerOption {
+	return func(target *Manager) {
+		target.rps = rps
+	}
+}
+
+// DefaultRequestsPerSecond is the rate limit of tenants that don't set their own, see WithDefaultRateLimit.
+const DefaultRequestsPerSecond = 10
+
+// defaultManagerHTTPClient clones the default transport, so the tenants share connections
+// without sharing them with the rest of the process.
+func defaultManagerHTTPClient() *http.Client {
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		return &http.Client{Transport: t.Clone()}
+	}
+	return &http.Client{}
+}
+
+// NewManager builds a client for every tenant. Requests are limited to DefaultRequestsPerSecond per token
+// unless the tenant or WithDefaultRateLimit sets another limit. Tenants sharing a token share its limit,
+// so they must end up with the same requests per second, NewManager fails otherwise.
+func NewManager(config map[string]TenantConfig, options ...ManagerOption) (*Manager, error) {
+	m := &Manager{
+		rps:     DefaultRequestsPerSecond,
+		clients: make(map[string]Client),
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.httpClient == nil {
+		m.httpClient = defaultManagerHTTPClient()
+	}
+
+	type sharedLimit struct {
+		tenant  string
+		rps     float64
+		limiter *rateLimiter
+	}
+
+	limits := make(map[string]sharedLimit)
+
+	tenants := make([]string, 0, len(config))
+	for tenant := range config {
+		tenants = append(tenants, tenant)
+	}
+	slices.Sort(tenants)
+
+	for _, tenant := range tenants {
+		cfg := config[tenant]
+		if cfg.Token == "" {
+			return nil, fmt.Errorf("tenant %s has no token", tenant)
+		}
+
+		rps := cfg.RequestsPerSecond
+		if rps == 0 {
+			rps = m.rps
+		}
+
+		limit, ok := limits[cfg.Token]
+		if ok && limit.rps != rps {
+			return nil, fmt.Errorf("tenants %s and %s share a token with different rate limits: %g and %g requests per second", limit.tenant, tenant, limit.rps, rps)
+		}
+		if !ok {
+			limit = sharedLimit{tenant: tenant, rps: rps}
+			if rps > 0 {
+				limit.limiter = newRateLimiter(rps)
+			}
+			limits[cfg.Token] = limit
+		}
+
+		httpClient := m.httpClient
+		if limit.limiter != nil {
+			httpClient = &rateLimitedHttpClient{HttpClient: m.httpClient, limiter: limit.limiter}
+		}
+
+		clientOptions := append(slices.Clone(m.options), WithHTTPClient(httpClient))
+		if cfg.Environment != "" {
+			clientOptions = append(clientOptions, WithEnvironment(cfg.Environment))
+		}
+		if cfg.TokenEnvironment != "" {
+			clientOptions = append(clientOptions, WithTokenEnvironment(cfg.TokenEnvironment))
+		}
+		if cfg.BaseURL != "" {
+			clientOptions = append(clientOptions, WithBaseURL(cfg.BaseURL))
+		}
+
+		m.clients[tenant] = NewClient(cfg.Token, clientOptions...)
+	}
+
+	return m, nil
+}
+
+// Client returns the client of the tenant.
+func (m *Manager) Client(tenant string) (Client, error) {
+	c, ok := m.clients[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, tenant)
+	}
+	return c, nil
+}
+
+// Tenants returns the tenant keys in sorted order.
+func (m *Manager) Tenants() []string {
+	tenants := make([]string, 0, len(m.clients))
+	for tenant := range m.clients {
+		tenants = append(tenants, tenant)
+	}
+	slices.Sort(tenants)
+	return tenants
+}
+
+// Health fetches the parent account of every tenant concurrently.
+func (m *Manager) Health(ctx context.Context) HealthReport {
+	tenants := m.Tenants()
+	report := HealthReport{Tenants: make([]TenantHealth, len(tenants))}
+
+	var wg sync.WaitGroup
+
+	for i, tenant := range tenants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			started := time.Now()
+			_, err := m.clients[tenant].FetchParentAccount(ctx)
+
+			h := TenantHealth{Tenant: tenant, Healthy: err == nil, Latency: time.Since(started), CheckedAt: started}
+			if err != nil {
+				h.Error = err.Error()
+			}
+			report.Tenants[i] = h
+		}()
+	}
+
+	wg.Wait()
+	return report
+}
+
+// rateLimiter spaces requests evenly, so at most rps requests are sent per second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateLimitedHttpClient struct {
+	HttpClient
+	limiter *rateLimiter
+}
+
+func (c *rateLimitedHttpClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return c.HttpClient.Do(req)
+}
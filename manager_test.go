@@ -0,0 +1,154 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Client(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	m, err := quidax.NewManager(map[string]quidax.TenantConfig{
+		"ng": {Token: "token-ng"},
+		"gh": {Token: "token-gh", BaseURL: "https://example.com"},
+	}, quidax.WithManagerHTTPClient(mockHttpClient))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"gh", "ng"}, m.Tenants())
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchMeOK))}
+	mockHttpClient.On("Do", bearer("token-gh")).Return(resp, nil).Once()
+
+	client, err := m.Client("gh")
+	require.NoError(t, err)
+
+	_, err = client.FetchParentAccount(context.TODO())
+	require.NoError(t, err)
+
+	_, err = m.Client("ke")
+	assert.ErrorIs(t, err, quidax.ErrUnknownTenant)
+}
+
+func TestManager_Health(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	m, err := quidax.NewManager(map[string]quidax.TenantConfig{
+		"ng": {Token: "token-ng"},
+		"gh": {Token: "token-gh"},
+	}, quidax.WithManagerHTTPClient(mockHttpClient))
+	require.NoError(t, err)
+
+	ok := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchMeOK))}
+	unauthorized := &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Unauthorized"}`)))}
+	mockHttpClient.On("Do", bearer("token-ng")).Return(ok, nil).Once()
+	mockHttpClient.On("Do", bearer("token-gh")).Return(unauthorized, nil).Once()
+
+	report := m.Health(context.TODO())
+	assert.False(t, report.Healthy())
+	require.Len(t, report.Tenants, 2)
+
+	assert.Equal(t, "gh", report.Tenants[0].Tenant)
+	assert.False(t, report.Tenants[0].Healthy)
+	assert.NotEmpty(t, report.Tenants[0].Error)
+	assert.True(t, report.Tenants[1].Healthy)
+}
+
+func TestManager_RateLimitIsSharedPerToken(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	m, err := quidax.NewManager(map[string]quidax.TenantConfig{
+		"a": {Token: "shared", RequestsPerSecond: 20},
+		"b": {Token: "shared", RequestsPerSecond: 20},
+	}, quidax.WithManagerHTTPClient(mockHttpClient))
+	require.NoError(t, err)
+
+	mockHttpClient.On("Do", bearer("shared")).Return(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchMeOK))}, nil
+	}).Times(4)
+
+	a, _ := m.Client("a")
+	b, _ := m.Client("b")
+
+	started := time.Now()
+	for _, c := range []quidax.Client{a, b, a, b} {
+		_, err := c.FetchParentAccount(context.TODO())
+		require.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(started), 140*time.Millisecond)
+}
+
+func TestNewManager_RequiresToken(t *testing.T) {
+	_, err := quidax.NewManager(map[string]quidax.TenantConfig{"ng": {}})
+	assert.Error(t, err)
+}
+
+func TestNewManager_ConflictingRateLimits(t *testing.T) {
+	_, err := quidax.NewManager(map[string]quidax.TenantConfig{
+		"a": {Token: "shared", RequestsPerSecond: 20},
+		"b": {Token: "shared"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenants a and b share a token with different rate limits")
+
+	_, err = quidax.NewManager(map[string]quidax.TenantConfig{
+		"a": {Token: "shared", RequestsPerSecond: quidax.DefaultRequestsPerSecond},
+		"b": {Token: "shared"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestManager_TokenEnvironment(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	m, err := quidax.NewManager(map[string]quidax.TenantConfig{
+		"ng": {Token: "sandbox-token", Environment: quidax.EnvironmentProduction, TokenEnvironment: quidax.EnvironmentSandbox},
+		"gh": {Token: "sandbox-token-gh", BaseURL: "https://proxy.example.com", Environment: quidax.EnvironmentProduction, TokenEnvironment: quidax.EnvironmentSandbox},
+	}, quidax.WithManagerHTTPClient(mockHttpClient))
+	require.NoError(t, err)
+
+	for _, tenant := range m.Tenants() {
+		client, err := m.Client(tenant)
+		require.NoError(t, err)
+
+		reporter, ok := client.(quidax.EnvironmentReporter)
+		require.True(t, ok)
+		assert.Equal(t, quidax.EnvironmentProduction, reporter.Environment())
+
+		_, err = client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+		assert.ErrorIs(t, err, quidax.ErrEnvironmentMismatch, tenant)
+	}
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewManager_CustomDefaultTransport(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+
+	http.DefaultTransport = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, http.ErrNotSupported
+	})
+
+	_, err := quidax.NewManager(map[string]quidax.TenantConfig{"ng": {Token: "token-ng"}}, quidax.WithManagerHTTPClient(quidax.NewMockHttpClient(t)))
+	require.NoError(t, err)
+
+	_, err = quidax.NewManager(map[string]quidax.TenantConfig{"ng": {Token: "token-ng"}})
+	require.NoError(t, err)
+}
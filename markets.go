@@ -0,0 +1,71 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type MarketsClient interface {
+	FetchMarkets(ctx context.Context) (MarketsResponse, error)
+	FetchTicker(ctx context.Context, market string) (TickerResponse, error)
+	FetchTickers(ctx context.Context) (TickersResponse, error)
+}
+
+type MarketData struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	BaseUnit  string `json:"base_unit"`
+	QuoteUnit string `json:"quote_unit"`
+}
+
+type MarketsResponse = Response[[]MarketData]
+
+func (c *client) FetchMarkets(ctx context.Context) (data MarketsResponse, err error) {
+	return call[[]MarketData](ctx, c, http.MethodGet, "/v1/markets", nil, nil, http.StatusOK)
+}
+
+type Ticker struct {
+	Buy  string `json:"buy"`
+	Sell string `json:"sell"`
+	Low  string `json:"low"`
+	High string `json:"high"`
+	Open string `json:"open"`
+	Last string `json:"last"`
+	Vol  string `json:"vol"`
+}
+
+func (t Ticker) GetBuy() float64 {
+	f, _ := strconv.ParseFloat(t.Buy, 64)
+	return f
+}
+
+func (t Ticker) GetSell() float64 {
+	f, _ := strconv.ParseFloat(t.Sell, 64)
+	return f
+}
+
+func (t Ticker) GetLast() float64 {
+	f, _ := strconv.ParseFloat(t.Last, 64)
+	return f
+}
+
+type TickerData struct {
+	At     int64  `json:"at"`
+	Market string `json:"market"`
+	Ticker Ticker `json:"ticker"`
+}
+
+type TickerResponse = Response[TickerData]
+
+func (c *client) FetchTicker(ctx context.Context, market string) (data TickerResponse, err error) {
+	return call[TickerData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/markets/tickers/%s", strings.ToLower(market)), nil, nil, http.StatusOK)
+}
+
+type TickersResponse = Response[map[string]TickerData]
+
+func (c *client) FetchTickers(ctx context.Context) (data TickersResponse, err error) {
+	return call[map[string]TickerData](ctx, c, http.MethodGet, "/v1/markets/tickers", nil, nil, http.StatusOK)
+}
@@ -0,0 +1,64 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/markets-fetch-all-ok.json
+var marketsFetchAllOk []byte
+
+//go:embed testdata/markets-tickers-ok.json
+var marketsTickersOk []byte
+
+//go:embed testdata/markets-ticker-btcusdt-ok.json
+var marketsTickerBtcusdtOk []byte
+
+func TestFetchMarkets_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(marketsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchMarkets(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, got.Data, 3)
+	assert.Equal(t, "btc", got.Data[0].BaseUnit)
+}
+
+func TestFetchTicker_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(marketsTickerBtcusdtOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchTicker(context.TODO(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 60000.0, got.Data.Ticker.GetLast())
+	assert.Equal(t, 59990.0, got.Data.Ticker.GetBuy())
+	assert.Equal(t, 60010.0, got.Data.Ticker.GetSell())
+}
+
+func TestFetchTickers_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(marketsTickersOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchTickers(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, got.Data, 3)
+	assert.Equal(t, 1500.0, got.Data["usdtngn"].Ticker.GetLast())
+}
@@ -0,0 +1,168 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrNoNetwork = errors.New("no enabled network matches the address")
+
+// DefaultAddressFormats maps networks to the format of their addresses.
+var DefaultAddressFormats = map[string]*regexp.Regexp{
+	string(NetworkBTC):   regexp.MustCompile(`^(bc1[a-z0-9]{25,87}|[13][a-km-zA-HJ-NP-Z1-9]{25,34})$`),
+	string(NetworkERC20): regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+	string(NetworkBEP20): regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+	string(NetworkBEP2):  regexp.MustCompile(`^bnb1[a-z0-9]{38}$`),
+	string(NetworkTRC20): regexp.MustCompile(`^T[1-9A-HJ-NP-Za-km-z]{33}$`),
+	string(NetworkSOL):   regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{43,44}$`),
+	string(NetworkXRP):   regexp.MustCompile(`^r[1-9A-HJ-NP-Za-km-z]{24,34}$`),
+	string(NetworkXLM):   regexp.MustCompile(`^G[A-Z2-7]{55}$`),
+	string(NetworkLTC):   regexp.MustCompile(`^(ltc1[a-z0-9]{25,87}|[LM3][a-km-zA-HJ-NP-Z1-9]{26,33})$`),
+}
+
+type NetworkOption struct {
+	Network string
+	Name    string
+	Default bool
+	// Fee is only set for withdrawal networks.
+	Fee float64
+}
+
+// NetworkSelector picks the network of a currency to withdraw or deposit on.
+type NetworkSelector struct {
+	client  Client
+	formats map[string]*regexp.Regexp
+}
+
+// NetworkSelectorOption is a function that configures a NetworkSelector.
+type NetworkSelectorOption func(*NetworkSelector)
+
+// WithAddressFormat sets the address format of the network, replacing the default one.
+func WithAddressFormat(network string, format *regexp.Regexp) NetworkSelectorOption {
+	return func(target *NetworkSelector) {
+		target.formats[strings.ToLower(network)] = format
+	}
+}
+
+func NewNetworkSelector(client Client, options ...NetworkSelectorOption) *NetworkSelector {
+	s := &NetworkSelector{client: client, formats: make(map[string]*regexp.Regexp)}
+	for network, format := range DefaultAddressFormats {
+		s.formats[network] = format
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// DepositNetworks returns the networks currently enabled for deposits, the default network first.
+func (s *NetworkSelector) DepositNetworks(ctx context.Context, userID uuid.UUID, currency string) ([]NetworkOption, error) {
+	wallet, err := s.client.FetchWallet(ctx, userID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+
+	options := make([]NetworkOption, 0)
+	for _, n := range wallet.Data.Networks {
+		if n.DepositsEnabled {
+			options = append(options, NetworkOption{Network: n.ID, Name: n.Name, Default: n.ID == wallet.Data.DefaultNetwork})
+		}
+	}
+
+	slices.SortStableFunc(options, compareDefault)
+
+	return options, nil
+}
+
+// WithdrawalNetworks returns the networks currently enabled for withdrawals ordered by the fee for the amount,
+// on equal fees the default network comes first. Networks without a fee tier for the amount are left out.
+func (s *NetworkSelector) WithdrawalNetworks(ctx context.Context, userID uuid.UUID, currency string, amount float64) ([]NetworkOption, error) {
+	wallet, err := s.client.FetchWallet(ctx, userID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+
+	options := make([]NetworkOption, 0)
+
+	for _, n := range wallet.Data.Networks {
+		if !n.WithdrawsEnabled {
+			continue
+		}
+
+		fees, err := s.client.FetchWithdrawalFees(ctx, currency, n.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s withdrawal fees: %w", n.ID, err)
+		}
+
+		fee, err := fees.GetFeeSchedule().FeeFor(amount)
+		if errors.Is(err, ErrNoFeeTier) {
+			continue
+		}
+
+		options = append(options, NetworkOption{Network: n.ID, Name: n.Name, Default: n.ID == wallet.Data.DefaultNetwork, Fee: fee})
+	}
+
+	slices.SortStableFunc(options, func(a, b NetworkOption) int {
+		if a.Fee != b.Fee {
+			if a.Fee < b.Fee {
+				return -1
+			}
+			return 1
+		}
+		return compareDefault(a, b)
+	})
+
+	return options, nil
+}
+
+// SelectWithdrawalNetwork returns the cheapest enabled network the address is valid on.
+// When the address format of no enabled network is known, the default network is returned.
+func (s *NetworkSelector) SelectWithdrawalNetwork(ctx context.Context, userID uuid.UUID, currency, address string, amount float64) (NetworkOption, error) {
+	options, err := s.WithdrawalNetworks(ctx, userID, currency, amount)
+	if err != nil {
+		return NetworkOption{}, err
+	}
+
+	known := false
+
+	for _, o := range options {
+		format, ok := s.formats[strings.ToLower(o.Network)]
+		if !ok {
+			continue
+		}
+
+		known = true
+		if format.MatchString(address) {
+			return o, nil
+		}
+	}
+
+	if !known {
+		for _, o := range options {
+			if o.Default {
+				return o, nil
+			}
+		}
+	}
+
+	return NetworkOption{}, ErrNoNetwork
+}
+
+func compareDefault(a, b NetworkOption) int {
+	switch {
+	case a.Default == b.Default:
+		return 0
+	case a.Default:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,86 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/wallets-fetch-usdt-ok.json
+var walletsFetchUsdtOk []byte
+
+func mockNetworkFees(mockHttpClient *quidax.MockHttpClient, fees map[string]float64) {
+	mockHttpClient.On("Do", requestPath("/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/wallets/usdt")).Return(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchUsdtOk))}, nil
+	})
+
+	for network, fee := range fees {
+		body := fmt.Sprintf(`{"status":"success","message":"Successful","data":{"fee":%v,"type":"flat"}}`, fee)
+		matcher := mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Path == "/v1/fee" && req.URL.Query().Get("network") == network
+		})
+		mockHttpClient.On("Do", matcher).Return(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+		})
+	}
+}
+
+func TestNetworkSelector_WithdrawalNetworks(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockNetworkFees(mockHttpClient, map[string]float64{"erc20": 5, "trc20": 1, "bep20": 1})
+
+	selector := quidax.NewNetworkSelector(client)
+
+	got, err := selector.WithdrawalNetworks(context.TODO(), uuid.MustParse("8269672d-d451-4ad2-88ac-bd70f1133615"), "usdt", 100)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "trc20", got[0].Network)
+	assert.True(t, got[0].Default)
+	assert.Equal(t, "bep20", got[1].Network)
+	assert.Equal(t, "erc20", got[2].Network)
+	assert.Equal(t, 5.0, got[2].Fee)
+}
+
+func TestNetworkSelector_SelectWithdrawalNetwork(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockNetworkFees(mockHttpClient, map[string]float64{"erc20": 5, "trc20": 1, "bep20": 2})
+
+	selector := quidax.NewNetworkSelector(client)
+	userID := uuid.MustParse("8269672d-d451-4ad2-88ac-bd70f1133615")
+
+	got, err := selector.SelectWithdrawalNetwork(context.TODO(), userID, "usdt", "0x52908400098527886E0F7030069857D2E4169EE7", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "bep20", got.Network)
+
+	got, err = selector.SelectWithdrawalNetwork(context.TODO(), userID, "usdt", "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "trc20", got.Network)
+
+	_, err = selector.SelectWithdrawalNetwork(context.TODO(), userID, "usdt", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", 100)
+	assert.ErrorIs(t, err, quidax.ErrNoNetwork)
+}
+
+func TestNetworkSelector_DepositNetworks(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockNetworkFees(mockHttpClient, nil)
+
+	got, err := quidax.NewNetworkSelector(client).DepositNetworks(context.TODO(), uuid.MustParse("8269672d-d451-4ad2-88ac-bd70f1133615"), "usdt")
+	require.NoError(t, err)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, "trc20", got[0].Network)
+}
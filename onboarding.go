@@ -0,0 +1,172 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type AddressRequest struct {
+	Currency string `json:"currency"`
+	Network  string `json:"network"`
+}
+
+type OnboardedAddress struct {
+	AddressRequest
+	Requested bool              `json:"requested"`
+	Address   WalletAddressData `json:"address"`
+	Error     string            `json:"error,omitempty"`
+}
+
+func (a OnboardedAddress) IsGenerated() bool {
+	return a.Address.Address != ""
+}
+
+// OnboardResult can be persisted and passed to Onboarder.Resume to finish a partial onboarding.
+type OnboardResult struct {
+	Account   AccountData        `json:"account"`
+	Addresses []OnboardedAddress `json:"addresses"`
+}
+
+// Failed returns the addresses that were not generated.
+func (r OnboardResult) Failed() []OnboardedAddress {
+	failed := make([]OnboardedAddress, 0)
+	for _, a := range r.Addresses {
+		if !a.IsGenerated() {
+			failed = append(failed, a)
+		}
+	}
+	return failed
+}
+
+// OnboardError is returned when some of the addresses were not generated.
+type OnboardError struct {
+	Failed []OnboardedAddress
+}
+
+func (e OnboardError) Error() string {
+	parts := make([]string, 0, len(e.Failed))
+	for _, a := range e.Failed {
+		parts = append(parts, fmt.Sprintf("%s/%s: %s", a.Currency, a.Network, a.Error))
+	}
+	return fmt.Sprintf("Failed to generate %d addresses: %s", len(e.Failed), strings.Join(parts, ", "))
+}
+
+type Onboarder struct {
+	client      Client
+	concurrency int
+	waitOptions []WaitOption
+}
+
+// OnboarderOption is a function that configures an Onboarder.
+type OnboarderOption func(*Onboarder)
+
+// WithOnboardConcurrency sets how many addresses are requested at the same time.
+func WithOnboardConcurrency(n int) OnboarderOption {
+	return func(target *Onboarder) {
+		target.concurrency = n
+	}
+}
+
+// WithOnboardWaitOptions sets how the generated addresses are polled.
+func WithOnboardWaitOptions(options ...WaitOption) OnboarderOption {
+	return func(target *Onboarder) {
+		target.waitOptions = options
+	}
+}
+
+func NewOnboarder(client Client, options ...OnboarderOption) *Onboarder {
+	o := &Onboarder{
+		client:      client,
+		concurrency: 4,
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	return o
+}
+
+// Onboard creates the account and generates the addresses for it.
+// When only some of the addresses are generated, the result is returned together with an OnboardError.
+func (o *Onboarder) Onboard(ctx context.Context, payload CreateAccountPayload, addresses []AddressRequest) (result OnboardResult, err error) {
+	resp, err := o.client.CreateAccount(ctx, payload)
+	if err != nil {
+		return result, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	result.Account = resp.Data
+	for _, a := range addresses {
+		result.Addresses = append(result.Addresses, OnboardedAddress{AddressRequest: a})
+	}
+
+	return o.Resume(ctx, result)
+}
+
+// Resume generates the addresses of the result that are not generated yet.
+func (o *Onboarder) Resume(ctx context.Context, result OnboardResult) (OnboardResult, error) {
+	user := o.client.ForUser(result.Account.ID)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(o.concurrency, 1))
+
+	for i := range result.Addresses {
+		if result.Addresses[i].IsGenerated() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(a *OnboardedAddress) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			a.Error = ""
+			if err := o.generate(ctx, user, a); err != nil {
+				a.Error = err.Error()
+			}
+		}(&result.Addresses[i])
+	}
+
+	wg.Wait()
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return result, OnboardError{Failed: failed}
+	}
+
+	return result, nil
+}
+
+func (o *Onboarder) generate(ctx context.Context, user UserClient, a *OnboardedAddress) error {
+	if !a.Requested {
+		resp, err := user.RequestWalletAddress(ctx, a.Currency, a.Network)
+		if err != nil {
+			return fmt.Errorf("failed to request address: %w", err)
+		}
+
+		a.Requested = true
+		a.Address = resp.Data
+	}
+
+	return newWaitConfig(o.waitOptions).poll(ctx, func() (bool, error) {
+		if a.IsGenerated() {
+			return true, nil
+		}
+
+		resp, err := user.FetchWalletAddresses(ctx, a.Currency)
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch addresses: %w", err)
+		}
+
+		for _, address := range resp.Data {
+			if address.ID == a.Address.ID {
+				a.Address = address
+			}
+		}
+
+		return a.IsGenerated(), nil
+	})
+}
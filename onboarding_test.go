@@ -0,0 +1,66 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/wallets-address-request-pending.json
+var walletsAddressRequestPending []byte
+
+func requestMethodPath(method, path string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == method && req.URL.Path == path
+	})
+}
+
+func TestOnboarder_Onboard(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	onboarder := quidax.NewOnboarder(client, quidax.WithOnboardWaitOptions(quidax.WithBackoff(time.Millisecond, time.Millisecond)))
+
+	user := "/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615"
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(accountsFetchMeOK))}
+	mockHttpClient.On("Do", requestMethodPath(http.MethodPost, "/v1/users")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(walletsAddressRequestPending))}
+	mockHttpClient.On("Do", requestMethodPath(http.MethodPost, user+"/wallets/btc/addresses")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsAddressFetchAllOk))}
+	mockHttpClient.On("Do", requestMethodPath(http.MethodGet, user+"/wallets/btc/addresses")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusUnprocessableEntity, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Unsupported"}`)))}
+	mockHttpClient.On("Do", requestMethodPath(http.MethodPost, user+"/wallets/xyz/addresses")).Return(resp, nil).Once()
+
+	got, err := onboarder.Onboard(context.TODO(), quidax.CreateAccountPayload{Email: "john@doe.com"}, []quidax.AddressRequest{
+		{Currency: "btc", Network: "btc"},
+		{Currency: "xyz"},
+	})
+
+	var onboardErr quidax.OnboardError
+	require.True(t, errors.As(err, &onboardErr))
+	require.Len(t, onboardErr.Failed, 1)
+	assert.Equal(t, "xyz", onboardErr.Failed[0].Currency)
+	assert.False(t, onboardErr.Failed[0].Requested)
+
+	assert.Equal(t, "dummyaddress", got.Addresses[0].Address.Address)
+
+	resp = &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(walletsAddressFetchBtcOk))}
+	mockHttpClient.On("Do", requestMethodPath(http.MethodPost, user+"/wallets/xyz/addresses")).Return(resp, nil).Once()
+
+	got, err = onboarder.Resume(context.TODO(), got)
+	require.NoError(t, err)
+	assert.Empty(t, got.Failed())
+}
@@ -0,0 +1,334 @@
+package quidax
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxState string
+
+const (
+	// OutboxPending intents were persisted but never sent.
+	OutboxPending OutboxState = "pending"
+	// OutboxSubmitting intents were sent, but it is unknown whether the API accepted them.
+	OutboxSubmitting OutboxState = "submitting"
+	OutboxSubmitted  OutboxState = "submitted"
+	OutboxFailed     OutboxState = "failed"
+)
+
+type WithdrawalIntent struct {
+	UserID     uuid.UUID               `json:"user_id"`
+	Payload    CreateWithdrawalPayload `json:"payload"`
+	State      OutboxState             `json:"state"`
+	Withdrawal WithdrawalData          `json:"withdrawal"`
+	Error      string                  `json:"error,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+func (i WithdrawalIntent) Reference() string {
+	return i.Payload.Reference
+}
+
+// ErrReferenceInUse is returned by Outbox.Send when the reference belongs to an intent with another payload.
+var ErrReferenceInUse = errors.New("reference is used by another withdrawal intent")
+
+// OutboxStore persists withdrawal intents by their reference.
+type OutboxStore interface {
+	Save(ctx context.Context, intent WithdrawalIntent) error
+	// Get returns the intent with the reference, the bool is false when there is none.
+	Get(ctx context.Context, reference string) (WithdrawalIntent, bool, error)
+	Unfinished(ctx context.Context) ([]WithdrawalIntent, error)
+}
+
+// Outbox persists every withdrawal before it is sent, so a withdrawal is never sent twice,
+// even when the process crashes before the response is received.
+// Intents are handled one at a time per reference, the store must not be shared by outboxes of other processes.
+type Outbox struct {
+	client WithdrawalsClient
+	store  OutboxStore
+	now    func() time.Time
+
+	mu    sync.Mutex
+	locks map[string]*referenceLock
+}
+
+type referenceLock struct {
+	sync.Mutex
+	holders int
+}
+
+func NewOutbox(client WithdrawalsClient, store OutboxStore) *Outbox {
+	return &Outbox{client: client, store: store, now: time.Now, locks: make(map[string]*referenceLock)}
+}
+
+// lock serialises the intents with the reference, the lock is dropped once nobody holds or waits for it.
+func (o *Outbox) lock(reference string) (unlock func()) {
+	o.mu.Lock()
+	l, ok := o.locks[reference]
+	if !ok {
+		l = &referenceLock{}
+		o.locks[reference] = l
+	}
+	l.holders++
+	o.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if l.holders--; l.holders == 0 {
+			delete(o.locks, reference)
+		}
+	}
+}
+
+// Send persists the withdrawal and submits it, a reference is generated when the payload has none.
+// When an intent with the reference exists it is returned as it is, or finished when it is unfinished.
+func (o *Outbox) Send(ctx context.Context, userID uuid.UUID, payload CreateWithdrawalPayload) (WithdrawalIntent, error) {
+	if payload.Reference == "" {
+		payload.Reference = uuid.NewString()
+	}
+
+	defer o.lock(payload.Reference)()
+
+	existing, ok, err := o.store.Get(ctx, payload.Reference)
+	if err != nil {
+		return existing, fmt.Errorf("failed to load withdrawal intent: %w", err)
+	}
+
+	if ok {
+		if existing.UserID != userID || existing.Payload != payload {
+			return existing, fmt.Errorf("%w: %s", ErrReferenceInUse, payload.Reference)
+		}
+		if existing.State == OutboxSubmitted || existing.State == OutboxFailed {
+			return existing, nil
+		}
+		return o.resume(ctx, existing)
+	}
+
+	now := o.now()
+	intent := WithdrawalIntent{UserID: userID, Payload: payload, State: OutboxPending, CreatedAt: now, UpdatedAt: now}
+
+	if err := o.save(ctx, &intent); err != nil {
+		return intent, err
+	}
+
+	return o.submit(ctx, intent)
+}
+
+func (o *Outbox) save(ctx context.Context, intent *WithdrawalIntent) error {
+	intent.UpdatedAt = o.now()
+	if err := o.store.Save(ctx, *intent); err != nil {
+		return fmt.Errorf("failed to save withdrawal intent: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) submit(ctx context.Context, intent WithdrawalIntent) (WithdrawalIntent, error) {
+	intent.State = OutboxSubmitting
+	if err := o.save(ctx, &intent); err != nil {
+		return intent, err
+	}
+
+	resp, err := o.client.CreateWithdrawal(ctx, intent.UserID, intent.Payload)
+	if err != nil {
+		// the API or the client rejected the withdrawal, anything else might have been processed
+		status, ok := responseStatus(err)
+		if (ok && status >= 400 && status < 500 && status != http.StatusConflict) || (!ok && rejected(err)) {
+			intent.State = OutboxFailed
+			if retryable(status) {
+				intent.State = OutboxPending
+			}
+			intent.Error = err.Error()
+			if saveErr := o.save(ctx, &intent); saveErr != nil {
+				return intent, saveErr
+			}
+		}
+		return intent, fmt.Errorf("failed to create withdrawal: %w", err)
+	}
+
+	intent.State = OutboxSubmitted
+	intent.Withdrawal = resp.Data
+	intent.Error = ""
+	return intent, o.save(ctx, &intent)
+}
+
+// retryable reports whether a withdrawal rejected with the status can be submitted again later.
+func retryable(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
+
+// Reconcile finishes the intents left over by a previous run. Intents that might have been sent
+// are looked up by reference first and only submitted again when the API doesn't know them.
+func (o *Outbox) Reconcile(ctx context.Context) ([]WithdrawalIntent, error) {
+	intents, err := o.store.Unfinished(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load withdrawal intents: %w", err)
+	}
+
+	var errs []error
+
+	for i, intent := range intents {
+		intents[i], err = o.reconcile(ctx, intent)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return intents, errors.Join(errs...)
+}
+
+// reconcile resumes the intent unless Send finished it since it was listed.
+func (o *Outbox) reconcile(ctx context.Context, intent WithdrawalIntent) (WithdrawalIntent, error) {
+	defer o.lock(intent.Reference())()
+
+	current, ok, err := o.store.Get(ctx, intent.Reference())
+	if err != nil {
+		return intent, fmt.Errorf("failed to load withdrawal intent: %w", err)
+	}
+	if ok {
+		intent = current
+	}
+
+	if intent.State == OutboxSubmitted || intent.State == OutboxFailed {
+		return intent, nil
+	}
+	return o.resume(ctx, intent)
+}
+
+// resume finishes an unfinished intent.
+func (o *Outbox) resume(ctx context.Context, intent WithdrawalIntent) (WithdrawalIntent, error) {
+	if intent.State == OutboxSubmitting {
+		resp, err := o.client.FetchWithdrawalByReference(ctx, intent.UserID, intent.Reference())
+		if err == nil {
+			intent.State = OutboxSubmitted
+			intent.Withdrawal = resp.Data
+			intent.Error = ""
+			return intent, o.save(ctx, &intent)
+		}
+
+		if status, ok := responseStatus(err); !ok || status != http.StatusNotFound {
+			return intent, fmt.Errorf("failed to fetch withdrawal %s: %w", intent.Reference(), err)
+		}
+	}
+
+	return o.submit(ctx, intent)
+}
+
+var _ OutboxStore = (*FileOutboxStore)(nil)
+
+// FileOutboxStore keeps the intents in a JSON file, which is replaced atomically on every save.
+type FileOutboxStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileOutboxStore(path string) *FileOutboxStore {
+	return &FileOutboxStore{path: path}
+}
+
+func (s *FileOutboxStore) load() (map[string]WithdrawalIntent, error) {
+	intents := make(map[string]WithdrawalIntent)
+
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return intents, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &intents); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox: %w", err)
+	}
+
+	return intents, nil
+}
+
+func (s *FileOutboxStore) Save(ctx context.Context, intent WithdrawalIntent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	intents[intent.Reference()] = intent
+
+	b, err := json.MarshalIndent(intents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Get returns the intent with the reference.
+func (s *FileOutboxStore) Get(ctx context.Context, reference string) (WithdrawalIntent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents, err := s.load()
+	if err != nil {
+		return WithdrawalIntent{}, false, err
+	}
+
+	intent, ok := intents[reference]
+	return intent, ok, nil
+}
+
+func (s *FileOutboxStore) Unfinished(ctx context.Context) ([]WithdrawalIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	unfinished := make([]WithdrawalIntent, 0)
+	for _, intent := range intents {
+		if intent.State == OutboxPending || intent.State == OutboxSubmitting {
+			unfinished = append(unfinished, intent)
+		}
+	}
+
+	slices.SortFunc(unfinished, func(a, b WithdrawalIntent) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return unfinished, nil
+}
@@ -0,0 +1,221 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func requestMethod(method string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == method
+	})
+}
+
+func TestOutbox_Send(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(resp, nil).Once()
+
+	got, err := quidax.NewOutbox(client, store).Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"})
+	require.NoError(t, err)
+	assert.Equal(t, quidax.OutboxSubmitted, got.State)
+
+	stored, ok, err := store.Get(context.TODO(), "ref-123")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, quidax.OutboxSubmitted, stored.State)
+}
+
+func TestOutbox_Reconcile(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox := quidax.NewOutbox(client, store)
+
+	// the process crashed while waiting for the response
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(nil, errors.New("connection reset")).Once()
+
+	got, err := outbox.Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"})
+	require.Error(t, err)
+	assert.Equal(t, quidax.OutboxSubmitting, got.State)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodGet)).Return(resp, nil).Once()
+
+	intents, err := outbox.Reconcile(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	assert.Equal(t, quidax.OutboxSubmitted, intents[0].State)
+	assert.Equal(t, "9e7b5c45-70d4-4aa6-8a84-df1a2d0e4c41", intents[0].Withdrawal.ID)
+
+	unfinished, err := store.Unfinished(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
+
+func TestOutbox_ReconcileResubmitsUnknown(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox := quidax.NewOutbox(client, store)
+
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(nil, errors.New("connection reset")).Once()
+
+	_, err := outbox.Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"})
+	require.Error(t, err)
+
+	resp := &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Not found"}`)))}
+	mockHttpClient.On("Do", requestMethod(http.MethodGet)).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(resp, nil).Once()
+
+	intents, err := outbox.Reconcile(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	assert.Equal(t, quidax.OutboxSubmitted, intents[0].State)
+}
+
+func TestOutbox_Rejected(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+
+	resp := &http.Response{StatusCode: http.StatusUnprocessableEntity, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Insufficient balance"}`)))}
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(resp, nil).Once()
+
+	got, err := quidax.NewOutbox(client, store).Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "100"})
+	require.Error(t, err)
+	assert.Equal(t, quidax.OutboxFailed, got.State)
+	assert.NotEmpty(t, got.Reference())
+}
+
+func TestOutbox_RejectedByClient(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithRegistry(quidax.DefaultRegistry()))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+
+	got, err := quidax.NewOutbox(client, store).Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1e-3"})
+	assert.ErrorAs(t, err, &quidax.ValidationError{})
+	assert.Equal(t, quidax.OutboxFailed, got.State)
+
+	unfinished, err := store.Unfinished(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, unfinished)
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestOutbox_SendConcurrently(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox := quidax.NewOutbox(client, store)
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).After(50*time.Millisecond).Return(resp, nil).Once()
+
+	userID := uuid.New()
+	payload := quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"}
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := outbox.Send(context.TODO(), userID, payload)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := outbox.Reconcile(context.TODO())
+		assert.NoError(t, err)
+	}()
+
+	wg.Wait()
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestOutbox_SendExistingReference(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox := quidax.NewOutbox(client, store)
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(resp, nil).Once()
+
+	userID := uuid.New()
+	payload := quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"}
+
+	first, err := outbox.Send(context.TODO(), userID, payload)
+	require.NoError(t, err)
+
+	again, err := outbox.Send(context.TODO(), userID, payload)
+	require.NoError(t, err)
+	assert.Equal(t, first.Withdrawal.ID, again.Withdrawal.ID)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 1)
+
+	payload.Amount = "0.2"
+	_, err = outbox.Send(context.TODO(), userID, payload)
+	assert.ErrorIs(t, err, quidax.ErrReferenceInUse)
+}
+
+func TestOutbox_SendReconcilesSubmitting(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	outbox := quidax.NewOutbox(client, store)
+
+	mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(nil, errors.New("connection reset")).Once()
+
+	userID := uuid.New()
+	payload := quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1", Reference: "ref-123"}
+
+	_, err := outbox.Send(context.TODO(), userID, payload)
+	require.Error(t, err)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", requestMethod(http.MethodGet)).Return(resp, nil).Once()
+
+	got, err := outbox.Send(context.TODO(), userID, payload)
+	require.NoError(t, err)
+	assert.Equal(t, quidax.OutboxSubmitted, got.State)
+}
+
+func TestOutbox_RetryableStaysPending(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusRequestTimeout, http.StatusTooManyRequests} {
+		mockHttpClient := quidax.NewMockHttpClient(t)
+		client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+		store := quidax.NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+
+		resp := &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Try again"}`)))}
+		mockHttpClient.On("Do", requestMethod(http.MethodPost)).Return(resp, nil).Once()
+
+		got, err := quidax.NewOutbox(client, store).Send(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+		require.Error(t, err)
+		assert.Equal(t, quidax.OutboxPending, got.State, "status %d", status)
+
+		unfinished, err := store.Unfinished(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, unfinished, 1, "status %d", status)
+	}
+}
@@ -0,0 +1,384 @@
+package quidax
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WithdrawalRequest is a withdrawal about to be sent to the API.
+type WithdrawalRequest struct {
+	// UserID is uuid.Nil for withdrawals from the parent account.
+	UserID  uuid.UUID
+	Payload CreateWithdrawalPayload
+	At      time.Time
+	// Usage is the store of the guard evaluating the request.
+	Usage UsageStore
+}
+
+func (r WithdrawalRequest) GetAmount() float64 {
+	f, _ := strconv.ParseFloat(r.Payload.Amount, 64)
+	return f
+}
+
+// Policy decides whether a withdrawal is allowed, returning a PolicyViolation if it is not.
+type Policy interface {
+	Evaluate(ctx context.Context, r WithdrawalRequest) error
+}
+
+// PolicyFunc is an adapter to allow the use of ordinary functions as Policy.
+type PolicyFunc func(ctx context.Context, r WithdrawalRequest) error
+
+func (f PolicyFunc) Evaluate(ctx context.Context, r WithdrawalRequest) error {
+	return f(ctx, r)
+}
+
+type PolicyViolation struct {
+	Policy   string
+	Reason   string
+	UserID   uuid.UUID
+	Currency string
+}
+
+func (v PolicyViolation) Error() string {
+	return fmt.Sprintf("Withdrawal violates %s policy. User: %s Currency: %s Reason: %s", v.Policy, v.UserID, v.Currency, v.Reason)
+}
+
+func violation(policy, reason string, r WithdrawalRequest) PolicyViolation {
+	return PolicyViolation{Policy: policy, Reason: reason, UserID: r.UserID, Currency: strings.ToLower(r.Payload.Currency)}
+}
+
+// LimitPolicy enforces per-transaction and rolling 24 hour limits per currency.
+// Currencies without a limit are not restricted. The daily usage is read from the store of the guard.
+type LimitPolicy struct {
+	PerTransaction map[string]float64
+	Daily          map[string]float64
+}
+
+// NewLimitPolicy returns a LimitPolicy with the currencies of the limits normalized.
+func NewLimitPolicy(perTransaction, daily map[string]float64) LimitPolicy {
+	return LimitPolicy{PerTransaction: lowerKeys(perTransaction), Daily: lowerKeys(daily)}
+}
+
+func (p LimitPolicy) Evaluate(ctx context.Context, r WithdrawalRequest) error {
+	currency := strings.ToLower(r.Payload.Currency)
+	amount := r.GetAmount()
+
+	if limit, ok := p.PerTransaction[currency]; ok && amount > limit {
+		return violation("limit", fmt.Sprintf("amount %g exceeds the per-transaction limit of %g", amount, limit), r)
+	}
+
+	limit, ok := p.Daily[currency]
+	if !ok {
+		return nil
+	}
+
+	if r.Usage == nil {
+		return errors.New("daily limit requires a usage store")
+	}
+
+	used, err := r.Usage.Usage(ctx, r.UserID, currency, r.At.Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	if used+amount > limit {
+		return violation("limit", fmt.Sprintf("amount %g with %g already withdrawn exceeds the daily limit of %g", amount, used, limit), r)
+	}
+
+	return nil
+}
+
+// AllowListPolicy restricts the destinations each sub-account can withdraw to.
+// Sub-accounts without an allow-list are not restricted.
+type AllowListPolicy struct {
+	Destinations map[uuid.UUID][]string
+}
+
+func (p AllowListPolicy) Evaluate(ctx context.Context, r WithdrawalRequest) error {
+	allowed, ok := p.Destinations[r.UserID]
+	if !ok || slices.Contains(allowed, r.Payload.FundUID) {
+		return nil
+	}
+	return violation("allow-list", fmt.Sprintf("destination %s is not allowed", r.Payload.FundUID), r)
+}
+
+// NetworkPolicy restricts the networks each currency can be withdrawn on.
+// Currencies without a list are not restricted.
+type NetworkPolicy struct {
+	Allowed map[string][]string
+}
+
+// NewNetworkPolicy returns a NetworkPolicy with the currencies and networks normalized.
+func NewNetworkPolicy(allowed map[string][]string) NetworkPolicy {
+	p := NetworkPolicy{Allowed: make(map[string][]string, len(allowed))}
+	for currency, networks := range allowed {
+		normalized := make([]string, 0, len(networks))
+		for _, n := range networks {
+			normalized = append(normalized, strings.ToLower(n))
+		}
+		p.Allowed[strings.ToLower(currency)] = append(p.Allowed[strings.ToLower(currency)], normalized...)
+	}
+	return p
+}
+
+func (p NetworkPolicy) Evaluate(ctx context.Context, r WithdrawalRequest) error {
+	allowed, ok := p.Allowed[strings.ToLower(r.Payload.Currency)]
+	if !ok || slices.Contains(allowed, strings.ToLower(r.Payload.Network)) {
+		return nil
+	}
+	return violation("network", fmt.Sprintf("network %q is not allowed", r.Payload.Network), r)
+}
+
+func lowerKeys(m map[string]float64) map[string]float64 {
+	if m == nil {
+		return nil
+	}
+
+	lower := make(map[string]float64, len(m))
+	for k, v := range m {
+		lower[strings.ToLower(k)] = v
+	}
+	return lower
+}
+
+// normalize returns the policy with its configuration normalized, policies of other types are returned as they are.
+func normalize(p Policy) Policy {
+	switch p := p.(type) {
+	case LimitPolicy:
+		return NewLimitPolicy(p.PerTransaction, p.Daily)
+	case *LimitPolicy:
+		n := NewLimitPolicy(p.PerTransaction, p.Daily)
+		return &n
+	case NetworkPolicy:
+		return NewNetworkPolicy(p.Allowed)
+	case *NetworkPolicy:
+		n := NewNetworkPolicy(p.Allowed)
+		return &n
+	default:
+		return p
+	}
+}
+
+// UsageStore keeps track of the withdrawn amounts for the rolling limits.
+type UsageStore interface {
+	Usage(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (float64, error)
+	Record(ctx context.Context, userID uuid.UUID, currency string, amount float64, at time.Time) error
+}
+
+type usageRecord struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Currency string    `json:"currency"`
+	Amount   float64   `json:"amount"`
+	At       time.Time `json:"at"`
+}
+
+func sumUsage(records []usageRecord, userID uuid.UUID, currency string, since time.Time) float64 {
+	var total float64
+	for _, r := range records {
+		if r.UserID == userID && r.Currency == currency && !r.At.Before(since) {
+			total += r.Amount
+		}
+	}
+	return total
+}
+
+var _ UsageStore = (*MemoryUsageStore)(nil)
+
+type MemoryUsageStore struct {
+	mu      sync.Mutex
+	records []usageRecord
+}
+
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{}
+}
+
+func (s *MemoryUsageStore) Usage(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sumUsage(s.records, userID, strings.ToLower(currency), since), nil
+}
+
+func (s *MemoryUsageStore) Record(ctx context.Context, userID uuid.UUID, currency string, amount float64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, usageRecord{UserID: userID, Currency: strings.ToLower(currency), Amount: amount, At: at})
+	return nil
+}
+
+var _ UsageStore = (*FileUsageStore)(nil)
+
+// FileUsageStore appends usage records to a JSON lines file.
+type FileUsageStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileUsageStore(path string) *FileUsageStore {
+	return &FileUsageStore{path: path}
+}
+
+func (s *FileUsageStore) Usage(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer f.Close()
+
+	var records []usageRecord
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r usageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return 0, fmt.Errorf("failed to decode usage record: %w", err)
+		}
+		records = append(records, r)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read usage file: %w", err)
+	}
+
+	return sumUsage(records, userID, strings.ToLower(currency), since), nil
+}
+
+func (s *FileUsageStore) Record(ctx context.Context, userID uuid.UUID, currency string, amount float64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(usageRecord{UserID: userID, Currency: strings.ToLower(currency), Amount: amount, At: at})
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+
+	return f.Sync()
+}
+
+// withdrawalGuard evaluates the policies before a withdrawal and records the amounts that might have been withdrawn.
+type withdrawalGuard struct {
+	store    UsageStore
+	policies []Policy
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+func newWithdrawalGuard(store UsageStore, policies []Policy) *withdrawalGuard {
+	g := &withdrawalGuard{store: store, now: time.Now}
+	for _, p := range policies {
+		g.policies = append(g.policies, normalize(p))
+	}
+	return g
+}
+
+// run sends the withdrawal when the policies allow it. Withdrawals are sent one at a time so limits can't be raced.
+// Dry runs are evaluated, but not recorded.
+func (g *withdrawalGuard) run(ctx context.Context, userID uuid.UUID, payload CreateWithdrawalPayload, dryRun bool, send func() (WithdrawalResponse, error)) (data WithdrawalResponse, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r := WithdrawalRequest{UserID: userID, Payload: payload, At: g.now(), Usage: g.store}
+
+	for _, p := range g.policies {
+		if err := p.Evaluate(ctx, r); err != nil {
+			return data, err
+		}
+	}
+
+	data, err = send()
+	if dryRun || (err != nil && rejected(err)) {
+		return data, err
+	}
+
+	// a withdrawal that failed in any other way might have been processed, so it counts against the limits
+	if recordErr := g.record(ctx, r); recordErr != nil {
+		return data, errors.Join(err, recordErr)
+	}
+
+	return data, err
+}
+
+func (g *withdrawalGuard) record(ctx context.Context, r WithdrawalRequest) error {
+	if g.store == nil {
+		return nil
+	}
+
+	if err := g.store.Record(ctx, r.UserID, r.Payload.Currency, r.GetAmount(), r.At); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	return nil
+}
+
+// rejected reports whether the withdrawal was refused before the API could process it.
+func rejected(err error) bool {
+	if status, ok := responseStatus(err); ok {
+		return status >= 400 && status < 500 && status != http.StatusConflict && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+	}
+
+	var (
+		policy     PolicyViolation
+		validation ValidationError
+		memo       MemoRequiredError
+	)
+
+	return errors.As(err, &policy) || errors.As(err, &validation) || errors.As(err, &memo) ||
+		errors.Is(err, ErrEnvironmentMismatch) || errors.Is(err, ErrReservedHeader)
+}
+
+var _ WithdrawalsClient = (*guardedWithdrawalsClient)(nil)
+
+type guardedWithdrawalsClient struct {
+	WithdrawalsClient
+	guard *withdrawalGuard
+}
+
+// NewGuardedWithdrawalsClient returns a WithdrawalsClient that evaluates the policies before every withdrawal,
+// and records the withdrawn amounts in the store. Withdrawals that fail in a way that leaves their outcome
+// unknown are recorded as well. Only withdrawals made through the returned client are guarded,
+// use WithWithdrawalGuard to guard every withdrawal of a client, including the ones of ForUser and ForParent.
+func NewGuardedWithdrawalsClient(client WithdrawalsClient, store UsageStore, policies ...Policy) WithdrawalsClient {
+	return &guardedWithdrawalsClient{WithdrawalsClient: client, guard: newWithdrawalGuard(store, policies)}
+}
+
+func (g *guardedWithdrawalsClient) CreateWithdrawal(ctx context.Context, userID uuid.UUID, payload CreateWithdrawalPayload) (WithdrawalResponse, error) {
+	return g.guard.run(ctx, userID, payload, dryRun(g.WithdrawalsClient), func() (WithdrawalResponse, error) {
+		return g.WithdrawalsClient.CreateWithdrawal(ctx, userID, payload)
+	})
+}
+
+// WithWithdrawalGuard makes the client evaluate the policies before every withdrawal, including the ones
+// made through ForUser and ForParent, and record the withdrawn amounts in the store.
+func WithWithdrawalGuard(store UsageStore, policies ...Policy) ClientOption {
+	return func(target *client) {
+		target.guard = newWithdrawalGuard(store, policies)
+	}
+}
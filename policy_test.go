@@ -0,0 +1,178 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardedWithdrawalsClient_DailyLimit(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	store := quidax.NewFileUsageStore(filepath.Join(t.TempDir(), "usage.jsonl"))
+	guarded := quidax.NewGuardedWithdrawalsClient(client, store, quidax.LimitPolicy{
+		PerTransaction: map[string]float64{"btc": 1},
+		Daily:          map[string]float64{"btc": 1.5},
+	})
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	userID := uuid.New()
+
+	_, err := guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "BTC", Amount: "1"})
+	require.NoError(t, err)
+
+	used, err := store.Usage(context.TODO(), userID, "btc", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, used)
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.6"})
+
+	var violation quidax.PolicyViolation
+	require.ErrorAs(t, err, &violation)
+	assert.Equal(t, "limit", violation.Policy)
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "2"})
+	require.ErrorAs(t, err, &violation)
+	assert.Contains(t, violation.Reason, "per-transaction")
+}
+
+func TestGuardedWithdrawalsClient_UppercaseConfig(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	guarded := quidax.NewGuardedWithdrawalsClient(client, quidax.NewMemoryUsageStore(),
+		quidax.LimitPolicy{PerTransaction: map[string]float64{"BTC": 1}},
+		quidax.NetworkPolicy{Allowed: map[string][]string{"USDT": {"TRC20"}}},
+	)
+
+	var violation quidax.PolicyViolation
+
+	_, err := guarded.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "2"})
+	require.ErrorAs(t, err, &violation)
+	assert.Equal(t, "limit", violation.Policy)
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "usdt", Network: "erc20", Amount: "2"})
+	require.ErrorAs(t, err, &violation)
+	assert.Equal(t, "network", violation.Policy)
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "usdt", Network: "trc20", Amount: "2"})
+	require.NoError(t, err)
+}
+
+func TestGuardedWithdrawalsClient_RecordsAmbiguousFailures(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	store := quidax.NewMemoryUsageStore()
+	guarded := quidax.NewGuardedWithdrawalsClient(client, store, quidax.NewLimitPolicy(nil, map[string]float64{"btc": 1.5}))
+
+	userID := uuid.New()
+
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(bytes.NewReader(nil))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err := guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1"})
+	require.Error(t, err)
+
+	resp = &http.Response{StatusCode: http.StatusUnprocessableEntity, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Insufficient balance"}`)))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.2"})
+	require.Error(t, err)
+
+	used, err := store.Usage(context.TODO(), userID, "btc", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, used, "only the withdrawal with an unknown outcome counts")
+
+	_, err = guarded.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1"})
+	assert.ErrorAs(t, err, &quidax.PolicyViolation{})
+}
+
+func TestWithWithdrawalGuard_ForUser(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	store := quidax.NewMemoryUsageStore()
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithWithdrawalGuard(store, quidax.NewLimitPolicy(nil, map[string]float64{"btc": 1.5})))
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	userID := uuid.New()
+
+	_, err := client.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1"})
+	require.NoError(t, err)
+
+	_, err = client.ForUser(userID).CreateWithdrawal(context.TODO(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1"})
+	assert.ErrorAs(t, err, &quidax.PolicyViolation{})
+}
+
+func TestWithWithdrawalGuard_DryRunIsNotRecorded(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	store := quidax.NewMemoryUsageStore()
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun(), quidax.WithWithdrawalGuard(store, quidax.NewLimitPolicy(nil, map[string]float64{"btc": 1})))
+
+	userID := uuid.New()
+
+	for range 3 {
+		_, err := client.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.5"})
+		require.NoError(t, err)
+	}
+
+	used, err := store.Usage(context.TODO(), userID, "btc", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, used)
+
+	_, err = client.CreateWithdrawal(context.TODO(), userID, quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "1.5"})
+	assert.ErrorAs(t, err, &quidax.PolicyViolation{}, "dry runs are still evaluated")
+
+	mockHttpClient.AssertNotCalled(t, "Do")
+}
+
+func TestAllowListPolicy(t *testing.T) {
+	userID := uuid.New()
+	policy := quidax.AllowListPolicy{Destinations: map[uuid.UUID][]string{userID: {"allowed"}}}
+
+	assert.NoError(t, policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{UserID: userID, Payload: quidax.CreateWithdrawalPayload{FundUID: "allowed"}}))
+	assert.NoError(t, policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{UserID: uuid.New(), Payload: quidax.CreateWithdrawalPayload{FundUID: "other"}}))
+
+	err := policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{UserID: userID, Payload: quidax.CreateWithdrawalPayload{FundUID: "other"}})
+	assert.ErrorAs(t, err, &quidax.PolicyViolation{})
+}
+
+func TestNetworkPolicy(t *testing.T) {
+	policy := quidax.NewNetworkPolicy(map[string][]string{"USDT": {"TRC20"}})
+
+	assert.NoError(t, policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{Payload: quidax.CreateWithdrawalPayload{Currency: "USDT", Network: "TRC20"}}))
+	assert.NoError(t, policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{Payload: quidax.CreateWithdrawalPayload{Currency: "btc"}}))
+
+	err := policy.Evaluate(context.TODO(), quidax.WithdrawalRequest{Payload: quidax.CreateWithdrawalPayload{Currency: "usdt", Network: "erc20"}})
+	assert.ErrorAs(t, err, &quidax.PolicyViolation{})
+}
+
+func TestMemoryUsageStore(t *testing.T) {
+	store := quidax.NewMemoryUsageStore()
+	userID := uuid.New()
+	now := time.Now()
+
+	require.NoError(t, store.Record(context.TODO(), userID, "btc", 1, now.Add(-25*time.Hour)))
+	require.NoError(t, store.Record(context.TODO(), userID, "btc", 2, now))
+
+	used, err := store.Usage(context.TODO(), userID, "BTC", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, used)
+}
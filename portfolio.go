@@ -0,0 +1,234 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrNoRateRoute = errors.New("no market route between currencies")
+
+// RateTable converts between currencies using the last traded price of every market,
+// routing through intermediate currencies when there is no direct market.
+type RateTable struct {
+	edges map[string]map[string]float64
+}
+
+func NewRateTable(markets []MarketData, tickers map[string]TickerData) RateTable {
+	t := RateTable{edges: make(map[string]map[string]float64)}
+
+	add := func(from, to string, rate float64) {
+		if t.edges[from] == nil {
+			t.edges[from] = make(map[string]float64)
+		}
+		t.edges[from][to] = rate
+	}
+
+	for _, m := range markets {
+		ticker, ok := tickers[m.ID]
+		if !ok {
+			continue
+		}
+
+		last := ticker.Ticker.GetLast()
+		if last <= 0 {
+			continue
+		}
+
+		base, quote := strings.ToLower(m.BaseUnit), strings.ToLower(m.QuoteUnit)
+		add(base, quote, last)
+		add(quote, base, 1/last)
+	}
+
+	return t
+}
+
+// Rate returns how much of to one unit of from is worth, and the currencies the conversion went through.
+func (t RateTable) Rate(from, to string) (float64, []string, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == to {
+		return 1, []string{from}, nil
+	}
+
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		neighbours := make([]string, 0, len(t.edges[current]))
+		for next := range t.edges[current] {
+			neighbours = append(neighbours, next)
+		}
+		slices.Sort(neighbours)
+
+		for _, next := range neighbours {
+			if _, seen := prev[next]; seen {
+				continue
+			}
+			prev[next] = current
+
+			if next != to {
+				queue = append(queue, next)
+				continue
+			}
+
+			route := []string{to}
+			for c := current; c != ""; c = prev[c] {
+				route = append([]string{c}, route...)
+			}
+
+			rate := 1.0
+			for i := 1; i < len(route); i++ {
+				rate *= t.edges[route[i-1]][route[i]]
+			}
+			return rate, route, nil
+		}
+	}
+
+	return 0, nil, ErrNoRateRoute
+}
+
+// TickerRateProvider is a RateProvider that uses the current market tickers.
+type TickerRateProvider struct {
+	client MarketsClient
+}
+
+func NewTickerRateProvider(client MarketsClient) TickerRateProvider {
+	return TickerRateProvider{client: client}
+}
+
+func (p TickerRateProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	table, err := fetchRateTable(ctx, p.client)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, _, err := table.Rate(from, to)
+	return rate, err
+}
+
+func fetchRateTable(ctx context.Context, client MarketsClient) (RateTable, error) {
+	markets, err := client.FetchMarkets(ctx)
+	if err != nil {
+		return RateTable{}, fmt.Errorf("failed to fetch markets: %w", err)
+	}
+
+	tickers, err := client.FetchTickers(ctx)
+	if err != nil {
+		return RateTable{}, fmt.Errorf("failed to fetch tickers: %w", err)
+	}
+
+	return NewRateTable(markets.Data, tickers.Data), nil
+}
+
+type AssetValue struct {
+	Currency string   `json:"currency"`
+	Balance  float64  `json:"balance"`
+	Rate     float64  `json:"rate"`
+	Value    float64  `json:"value"`
+	Route    []string `json:"route"`
+}
+
+type PortfolioValuation struct {
+	Currency string             `json:"currency"`
+	Total    float64            `json:"total"`
+	Assets   []AssetValue       `json:"assets"`
+	Rates    map[string]float64 `json:"rates"`
+	Unpriced []string           `json:"unpriced"`
+}
+
+// Portfolio values wallet balances in a reference currency, locked funds are part of the balance.
+type Portfolio struct {
+	client Client
+}
+
+func NewPortfolio(client Client) *Portfolio {
+	return &Portfolio{client: client}
+}
+
+// Value returns the valuation of the wallets of a single account.
+func (p *Portfolio) Value(ctx context.Context, user UserClient, currency string) (PortfolioValuation, error) {
+	wallets, err := user.FetchWallets(ctx)
+	if err != nil {
+		return PortfolioValuation{}, fmt.Errorf("failed to fetch wallets: %w", err)
+	}
+
+	table, err := fetchRateTable(ctx, p.client)
+	if err != nil {
+		return PortfolioValuation{}, err
+	}
+
+	return value(table, currency, wallets.Data), nil
+}
+
+// ValueAccounts returns the combined valuation of the wallets of all sub-accounts.
+func (p *Portfolio) ValueAccounts(ctx context.Context, currency string) (PortfolioValuation, error) {
+	var wallets []WalletData
+
+	for page := 1; ; page++ {
+		accounts, err := p.client.FetchAccounts(ctx, page)
+		if err != nil {
+			return PortfolioValuation{}, fmt.Errorf("failed to fetch accounts: %w", err)
+		}
+
+		if len(accounts.Data) == 0 {
+			break
+		}
+
+		for _, account := range accounts.Data {
+			resp, err := p.client.ForUser(account.ID).FetchWallets(ctx)
+			if err != nil {
+				return PortfolioValuation{}, fmt.Errorf("failed to fetch wallets of %s: %w", account.ID, err)
+			}
+			wallets = append(wallets, resp.Data...)
+		}
+	}
+
+	table, err := fetchRateTable(ctx, p.client)
+	if err != nil {
+		return PortfolioValuation{}, err
+	}
+
+	return value(table, currency, wallets), nil
+}
+
+func value(table RateTable, currency string, wallets []WalletData) PortfolioValuation {
+	v := PortfolioValuation{Currency: strings.ToLower(currency), Rates: make(map[string]float64)}
+
+	balances := make(map[string]float64)
+	for _, w := range wallets {
+		balances[strings.ToLower(w.Currency)] += w.GetBalance()
+	}
+
+	currencies := make([]string, 0, len(balances))
+	for c := range balances {
+		currencies = append(currencies, c)
+	}
+	slices.Sort(currencies)
+
+	for _, c := range currencies {
+		asset := AssetValue{Currency: c, Balance: balances[c]}
+
+		rate, route, err := table.Rate(c, v.Currency)
+		if err != nil {
+			if asset.Balance != 0 {
+				v.Unpriced = append(v.Unpriced, c)
+			}
+			v.Assets = append(v.Assets, asset)
+			continue
+		}
+
+		asset.Rate, asset.Route = rate, route
+		asset.Value = asset.Balance * rate
+
+		v.Rates[c] = rate
+		v.Total += asset.Value
+		v.Assets = append(v.Assets, asset)
+	}
+
+	return v
+}
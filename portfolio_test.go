@@ -0,0 +1,86 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/wallets-fetch-portfolio-ok.json
+var walletsFetchPortfolioOk []byte
+
+func mockRates(mockHttpClient *quidax.MockHttpClient) {
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(marketsFetchAllOk))}
+	mockHttpClient.On("Do", requestPath("/v1/markets")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(marketsTickersOk))}
+	mockHttpClient.On("Do", requestPath("/v1/markets/tickers")).Return(resp, nil).Once()
+}
+
+func TestRateTable_Rate(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockRates(mockHttpClient)
+
+	markets, err := client.FetchMarkets(context.TODO())
+	require.NoError(t, err)
+	tickers, err := client.FetchTickers(context.TODO())
+	require.NoError(t, err)
+
+	table := quidax.NewRateTable(markets.Data, tickers.Data)
+
+	rate, route, err := table.Rate("ETH", "NGN")
+	require.NoError(t, err)
+	assert.InDelta(t, 4_500_000, rate, 1e-6)
+	assert.Equal(t, []string{"eth", "btc", "usdt", "ngn"}, route)
+
+	rate, _, err = table.Rate("ngn", "usdt")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0/1500, rate, 1e-12)
+
+	_, _, err = table.Rate("xyz", "usdt")
+	assert.ErrorIs(t, err, quidax.ErrNoRateRoute)
+}
+
+func TestPortfolio_Value(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchPortfolioOk))}
+	mockHttpClient.On("Do", requestPath("/v1/users/me/wallets")).Return(resp, nil).Once()
+	mockRates(mockHttpClient)
+
+	got, err := quidax.NewPortfolio(client).Value(context.TODO(), client.ForParent(), "NGN")
+	require.NoError(t, err)
+	assert.InDelta(t, 99_001_000, got.Total, 1e-3)
+	assert.Equal(t, 1.0, got.Assets[0].Balance, "locked funds are part of the balance")
+	assert.Len(t, got.Assets, 4)
+	assert.Equal(t, []string{"xyz"}, got.Unpriced)
+	assert.Equal(t, 1.0, got.Rates["ngn"])
+}
+
+func TestPortfolio_ValueAccounts(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchAllOK))}
+	mockHttpClient.On("Do", accountsPage("1")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(accountsFetchAllEmpty))}
+	mockHttpClient.On("Do", accountsPage("2")).Return(resp, nil).Once()
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", requestPath("/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/wallets")).Return(resp, nil).Once()
+	mockRates(mockHttpClient)
+
+	got, err := quidax.NewPortfolio(client).ValueAccounts(context.TODO(), "usdt")
+	require.NoError(t, err)
+	assert.InDelta(t, 600_000, got.Total, 1e-6)
+}
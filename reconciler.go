@@ -0,0 +1,283 @@
+package quidax
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransactionKind string
+
+const (
+	TransactionDeposit    TransactionKind = "deposit"
+	TransactionWithdrawal TransactionKind = "withdrawal"
+	TransactionSwap       TransactionKind = "swap"
+)
+
+// LedgerRecord is a transaction as seen by either our ledger or Quidax.
+// Records are matched by Reference first and by TxID second.
+type LedgerRecord struct {
+	Kind      TransactionKind `json:"kind"`
+	UserID    string          `json:"user_id"`
+	Reference string          `json:"reference"`
+	TxID      string          `json:"txid"`
+	Currency  string          `json:"currency"`
+	Amount    float64         `json:"amount"`
+	At        time.Time       `json:"at"`
+}
+
+// LedgerSource returns our own records for the [from, to) range.
+type LedgerSource interface {
+	Records(ctx context.Context, from, to time.Time) ([]LedgerRecord, error)
+}
+
+type ReconcileMatch struct {
+	Ours   LedgerRecord `json:"ours"`
+	Quidax LedgerRecord `json:"quidax"`
+}
+
+type ReconcileReport struct {
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	Matched          []ReconcileMatch `json:"matched"`
+	AmountMismatch   []ReconcileMatch `json:"amount_mismatch"`
+	MissingOnOurSide []LedgerRecord   `json:"missing_on_our_side"`
+	MissingOnQuidax  []LedgerRecord   `json:"missing_on_quidax"`
+}
+
+func (r ReconcileReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row per record, matched pairs are written on a single row.
+func (r ReconcileReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"status", "kind", "user_id", "reference", "txid", "currency", "our_amount", "quidax_amount", "at"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	amount := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+	row := func(status string, rec LedgerRecord, ours, theirs string) []string {
+		return []string{status, string(rec.Kind), rec.UserID, rec.Reference, rec.TxID, rec.Currency, ours, theirs, rec.At.Format(time.RFC3339)}
+	}
+
+	for _, m := range r.Matched {
+		cw.Write(row("matched", m.Quidax, amount(m.Ours.Amount), amount(m.Quidax.Amount)))
+	}
+	for _, m := range r.AmountMismatch {
+		cw.Write(row("amount_mismatch", m.Quidax, amount(m.Ours.Amount), amount(m.Quidax.Amount)))
+	}
+	for _, rec := range r.MissingOnOurSide {
+		cw.Write(row("missing_on_our_side", rec, "", amount(rec.Amount)))
+	}
+	for _, rec := range r.MissingOnQuidax {
+		cw.Write(row("missing_on_quidax", rec, amount(rec.Amount), ""))
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type Reconciler struct {
+	client    Client
+	source    LedgerSource
+	tolerance float64
+}
+
+// ReconcilerOption is a function that configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithAmountTolerance sets the largest difference between amounts that still counts as a match.
+func WithAmountTolerance(f float64) ReconcilerOption {
+	return func(target *Reconciler) {
+		target.tolerance = f
+	}
+}
+
+func NewReconciler(client Client, source LedgerSource, options ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:    client,
+		source:    source,
+		tolerance: 1e-8,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Run matches our records against the transactions of the parent account and all sub-accounts in the [from, to) range.
+func (r *Reconciler) Run(ctx context.Context, from, to time.Time) (report ReconcileReport, err error) {
+	report = ReconcileReport{From: from, To: to}
+
+	theirs, err := r.fetch(ctx, from, to)
+	if err != nil {
+		return report, err
+	}
+
+	ours, err := r.source.Records(ctx, from, to)
+	if err != nil {
+		return report, fmt.Errorf("failed to fetch ledger records: %w", err)
+	}
+
+	byReference := make(map[string]int)
+	byTxID := make(map[string]int)
+	for i, rec := range theirs {
+		if rec.Reference != "" {
+			byReference[string(rec.Kind)+":"+rec.Reference] = i
+		}
+		if rec.TxID != "" {
+			byTxID[string(rec.Kind)+":"+rec.TxID] = i
+		}
+	}
+
+	matched := make([]bool, len(theirs))
+
+	for _, rec := range ours {
+		i, ok := byReference[string(rec.Kind)+":"+rec.Reference]
+		if !ok || rec.Reference == "" || matched[i] {
+			i, ok = byTxID[string(rec.Kind)+":"+rec.TxID]
+			if !ok || rec.TxID == "" || matched[i] {
+				report.MissingOnQuidax = append(report.MissingOnQuidax, rec)
+				continue
+			}
+		}
+
+		matched[i] = true
+		m := ReconcileMatch{Ours: rec, Quidax: theirs[i]}
+
+		if !strings.EqualFold(rec.Currency, theirs[i].Currency) || math.Abs(rec.Amount-theirs[i].Amount) > r.tolerance {
+			report.AmountMismatch = append(report.AmountMismatch, m)
+			continue
+		}
+
+		report.Matched = append(report.Matched, m)
+	}
+
+	for i, rec := range theirs {
+		if !matched[i] {
+			report.MissingOnOurSide = append(report.MissingOnOurSide, rec)
+		}
+	}
+
+	return report, nil
+}
+
+func (r *Reconciler) fetch(ctx context.Context, from, to time.Time) ([]LedgerRecord, error) {
+	parent, err := r.client.FetchParentAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch parent account: %w", err)
+	}
+
+	users := []uuid.UUID{parent.Data.ID}
+
+	for page := 1; ; page++ {
+		accounts, err := r.client.FetchAccounts(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch accounts: %w", err)
+		}
+
+		if len(accounts.Data) == 0 {
+			break
+		}
+
+		for _, account := range accounts.Data {
+			if account.ID != parent.Data.ID {
+				users = append(users, account.ID)
+			}
+		}
+	}
+
+	inRange := func(at time.Time) bool {
+		return !at.Before(from) && at.Before(to)
+	}
+
+	var records []LedgerRecord
+
+	for _, id := range users {
+		user := r.client.ForUser(id)
+
+		deposits, err := fetchPages(func(page int) (DepositsResponse, error) {
+			return user.FetchDeposits(ctx, "", "", page)
+		}, func(d DepositData) string { return d.ID })
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deposits of %s: %w", id, err)
+		}
+
+		for _, d := range deposits {
+			if inRange(d.CreatedAt) {
+				records = append(records, LedgerRecord{Kind: TransactionDeposit, UserID: id.String(), Reference: d.ID, TxID: d.TxID, Currency: strings.ToLower(d.Currency), Amount: d.GetAmount(), At: d.CreatedAt})
+			}
+		}
+
+		withdrawals, err := fetchPages(func(page int) (WithdrawalsResponse, error) {
+			return user.FetchWithdrawals(ctx, "", "", page)
+		}, func(w WithdrawalData) string { return w.ID })
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch withdrawals of %s: %w", id, err)
+		}
+
+		for _, w := range withdrawals {
+			if inRange(w.CreatedAt) {
+				records = append(records, LedgerRecord{Kind: TransactionWithdrawal, UserID: id.String(), Reference: w.Reference, TxID: w.TxID, Currency: strings.ToLower(w.Currency), Amount: w.GetAmount(), At: w.CreatedAt})
+			}
+		}
+
+		swaps, err := fetchPages(func(page int) (SwapTransactionsResponse, error) {
+			return user.FetchSwapTransactions(ctx, page)
+		}, func(s SwapTransactionData) string { return s.ID })
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch swap transactions of %s: %w", id, err)
+		}
+
+		for _, s := range swaps {
+			if inRange(s.CreatedAt) {
+				records = append(records, LedgerRecord{Kind: TransactionSwap, UserID: id.String(), Reference: s.ID, Currency: strings.ToLower(s.FromCurrency), Amount: s.GetFromAmount(), At: s.CreatedAt})
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// fetchPages fetches pages until one is empty or only repeats items of the previous ones,
+// the latter happens when the endpoint ignores the page. The ID of the items is used to tell them apart.
+func fetchPages[T any](fetch func(page int) (Response[[]T], error), id func(T) string) ([]T, error) {
+	var items []T
+	seen := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		resp, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, item := range resp.Data {
+			if key := id(item); !seen[key] {
+				seen[key] = true
+				items = append(items, item)
+				added++
+			}
+		}
+
+		if added == 0 {
+			return items, nil
+		}
+	}
+}
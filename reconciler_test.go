@@ -0,0 +1,157 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/deposits-fetch-all-ok.json
+var depositsFetchAllOk []byte
+
+//go:embed testdata/withdrawals-fetch-all-ok.json
+var withdrawalsFetchAllOk []byte
+
+//go:embed testdata/swap-transactions-fetch-all-ok.json
+var swapTransactionsFetchAllOk []byte
+
+//go:embed testdata/deposits-fetch-all-empty.json
+var depositsFetchAllEmpty []byte
+
+//go:embed testdata/withdrawals-fetch-all-empty.json
+var withdrawalsFetchAllEmpty []byte
+
+//go:embed testdata/withdrawals-fetch-all-page2.json
+var withdrawalsFetchAllPage2 []byte
+
+//go:embed testdata/swap-transactions-fetch-all-empty.json
+var swapTransactionsFetchAllEmpty []byte
+
+type ledger []quidax.LedgerRecord
+
+func (l ledger) Records(ctx context.Context, from, to time.Time) ([]quidax.LedgerRecord, error) {
+	return l, nil
+}
+
+func TestReconciler_Run(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	parent := "/v1/users/0b3f9c1e-6a51-4c0b-9d0c-3c8a2f4d7e21"
+	user := "/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615"
+
+	responses := []struct {
+		match interface{}
+		body  []byte
+	}{
+		{requestPath("/v1/users/me"), accountsFetchParentOK},
+		{accountsPage("1"), accountsFetchAllOK},
+		{accountsPage("2"), accountsFetchAllEmpty},
+		{requestPath(parent + "/deposits"), depositsFetchAllEmpty},
+		{requestPath(parent + "/withdraws"), withdrawalsFetchAllEmpty},
+		{requestPath(parent + "/swap_transactions"), swapTransactionsFetchAllEmpty},
+		{pagePath(user+"/deposits", "1"), depositsFetchAllOk},
+		{pagePath(user+"/deposits", "2"), depositsFetchAllEmpty},
+		{pagePath(user+"/withdraws", "1"), withdrawalsFetchAllOk},
+		{pagePath(user+"/withdraws", "2"), withdrawalsFetchAllEmpty},
+		{pagePath(user+"/swap_transactions", "1"), swapTransactionsFetchAllOk},
+		{pagePath(user+"/swap_transactions", "2"), swapTransactionsFetchAllEmpty},
+	}
+
+	for _, r := range responses {
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(r.body))}
+		mockHttpClient.On("Do", r.match).Return(resp, nil).Once()
+	}
+
+	ours := ledger{
+		{Kind: quidax.TransactionDeposit, TxID: "tx-dep", Currency: "BTC", Amount: 0.5},
+		{Kind: quidax.TransactionWithdrawal, Reference: "ref-123", Currency: "btc", Amount: 0.02},
+		{Kind: quidax.TransactionWithdrawal, Reference: "ref-999", Currency: "btc", Amount: 0.1},
+		{Kind: quidax.TransactionSwap, Reference: "7d1a4c2e-9b8f-4e3d-a2c1-5f6e7d8c9b0a", Currency: "eth", Amount: 0.01},
+	}
+
+	from := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	got, err := quidax.NewReconciler(client, ours).Run(context.TODO(), from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, got.Matched, 2)
+	require.Len(t, got.AmountMismatch, 1)
+	assert.Equal(t, "ref-123", got.AmountMismatch[0].Quidax.Reference)
+	require.Len(t, got.MissingOnQuidax, 1)
+	assert.Equal(t, "ref-999", got.MissingOnQuidax[0].Reference)
+	require.Len(t, got.MissingOnOurSide, 1)
+	assert.Equal(t, "ref-456", got.MissingOnOurSide[0].Reference)
+
+	var buf bytes.Buffer
+	require.NoError(t, got.WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 6)
+	assert.Equal(t, "amount_mismatch", rows[3][0])
+
+	buf.Reset()
+	require.NoError(t, got.WriteJSON(&buf))
+	assert.Contains(t, buf.String(), `"missing_on_quidax"`)
+}
+
+func pagePath(path, page string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == path && req.URL.Query().Get("page") == page
+	})
+}
+
+func TestReconciler_RunFetchesAllPages(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	parent := "/v1/users/0b3f9c1e-6a51-4c0b-9d0c-3c8a2f4d7e21"
+	user := "/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615"
+
+	responses := []struct {
+		match interface{}
+		body  []byte
+	}{
+		{requestPath("/v1/users/me"), accountsFetchParentOK},
+		{accountsPage("1"), accountsFetchAllOK},
+		{accountsPage("2"), accountsFetchAllEmpty},
+		{pagePath(parent+"/deposits", "1"), depositsFetchAllEmpty},
+		{pagePath(parent+"/withdraws", "1"), withdrawalsFetchAllEmpty},
+		{pagePath(parent+"/swap_transactions", "1"), swapTransactionsFetchAllEmpty},
+		{pagePath(user+"/deposits", "1"), depositsFetchAllOk},
+		{pagePath(user+"/deposits", "2"), depositsFetchAllEmpty},
+		{pagePath(user+"/withdraws", "1"), withdrawalsFetchAllOk},
+		{pagePath(user+"/withdraws", "2"), withdrawalsFetchAllPage2},
+		{pagePath(user+"/withdraws", "3"), withdrawalsFetchAllEmpty},
+		{pagePath(user+"/swap_transactions", "1"), swapTransactionsFetchAllOk},
+		// the page is ignored, so the first page is returned again
+		{pagePath(user+"/swap_transactions", "2"), swapTransactionsFetchAllOk},
+	}
+
+	for _, r := range responses {
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(r.body))}
+		mockHttpClient.On("Do", r.match).Return(resp, nil).Once()
+	}
+
+	ours := ledger{
+		{Kind: quidax.TransactionWithdrawal, Reference: "ref-999", Currency: "btc", Amount: 0.1},
+	}
+
+	from := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	got, err := quidax.NewReconciler(client, ours).Run(context.TODO(), from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, got.Matched, 1)
+	assert.Equal(t, "ref-999", got.Matched[0].Quidax.Reference)
+	assert.Empty(t, got.MissingOnQuidax)
+}
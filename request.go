@@ -1,12 +1,17 @@
 package quidax
 
 import (
+	"context"
 	"net/http"
 )
 
 // request is a wrapper around http.Request.
 type request struct {
 	req              *http.Request
+	body             []byte
+	token            string
+	cancel           context.CancelFunc
+	skipLogging      bool
 	expectedStatuses []int
 	decodeTo         interface{}
 }
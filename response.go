@@ -0,0 +1,63 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Response is the envelope every API response is wrapped in.
+type Response[T any] struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+// call sends a request to the path and decodes the response envelope, query and body are optional.
+func call[T any](ctx context.Context, c *client, method, path string, query url.Values, body interface{}, expectedStatuses ...int) (data Response[T], err error) {
+	return data, c.call(ctx, method, path, query, body, &data, expectedStatuses...)
+}
+
+// Do calls an endpoint that is not wrapped by the client yet, with the same authentication, base URL,
+// logging, dry-run and error handling as the wrapped ones. The path is relative to the base URL,
+// e.g. "/v1/users/me/wallets", query and body are optional, and the response is decoded into out when it is not nil.
+// Without expected statuses any of 200, 201, 202 and 204 is accepted.
+//
+//	var resp quidax.Response[[]quidax.WalletData]
+//	err := client.Do(ctx, http.MethodGet, "/v1/users/me/wallets", nil, nil, &resp)
+func (c *client) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}, expectedStatuses ...int) error {
+	if len(expectedStatuses) == 0 {
+		expectedStatuses = []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent}
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return c.call(ctx, method, path, query, body, out, expectedStatuses...)
+}
+
+func (c *client) call(ctx context.Context, method, path string, query url.Values, body, out interface{}, expectedStatuses ...int) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if len(query) > 0 {
+		// values in the path are kept, unless the query sets them too
+		merged := req.req.URL.Query()
+		for key, values := range query {
+			merged[key] = values
+		}
+		req.req.URL.RawQuery = merged.Encode()
+	}
+
+	if out != nil {
+		req.DecodeTo(out)
+	}
+
+	req.ExpectStatus(expectedStatuses...)
+	return c.do(ctx, req)
+}
@@ -0,0 +1,96 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponse_ErrorStatusWithSuccessfulCode(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	body := `{"status":"error","message":"Insufficient balance","data":{"code":"insufficient_balance","message":"Insufficient balance"}}`
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err := client.FetchWallet(context.TODO(), uuid.New(), "btc")
+
+	var errResponse quidax.ErrResponse
+	require.ErrorAs(t, err, &errResponse)
+	assert.Equal(t, http.StatusOK, errResponse.StatusCode)
+	assert.Equal(t, "insufficient_balance", errResponse.Data.Code)
+}
+
+func TestResponse_QueryParams(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	withQuery := mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("currency") == "btc" && req.URL.Query().Get("state") == "done"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchAllOk))}
+	mockHttpClient.On("Do", withQuery).Return(resp, nil).Once()
+
+	got, err := client.FetchWithdrawals(context.TODO(), uuid.New(), "BTC", "done", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "success", got.Status)
+}
+
+func TestDo(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	withQuery := mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.Path == "/v1/users/me/wallets" && req.URL.Query().Get("page") == "2" && req.Header.Get("Authorization") == "Bearer token"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", withQuery).Return(resp, nil).Once()
+
+	var got quidax.Response[[]quidax.WalletData]
+	err := client.Do(context.TODO(), http.MethodGet, "v1/users/me/wallets", url.Values{"page": {"2"}}, nil, &got)
+	require.NoError(t, err)
+	require.Len(t, got.Data, 1)
+	assert.Equal(t, "btc", got.Data[0].Currency)
+}
+
+func TestDo_MergesPathQuery(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	merged := mock.MatchedBy(func(req *http.Request) bool {
+		q := req.URL.Query()
+		return req.URL.Path == "/v1/users/me/deposits" && q.Get("currency") == "btc" && q.Get("state") == "accepted" && q.Get("page") == "2"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", merged).Return(resp, nil).Once()
+
+	err := client.Do(context.TODO(), http.MethodGet, "/v1/users/me/deposits?currency=btc&page=1", url.Values{"state": {"accepted"}, "page": {"2"}}, nil, nil)
+	require.NoError(t, err)
+}
+
+func TestDo_UnexpectedStatus(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(bytes.NewReader(nil))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	err := client.Do(context.TODO(), http.MethodDelete, "/v1/something", nil, nil, nil, http.StatusOK)
+
+	var unexpected quidax.UnexpectedResponse
+	require.ErrorAs(t, err, &unexpected)
+	assert.Equal(t, http.StatusNoContent, unexpected.Status)
+}
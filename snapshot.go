@@ -0,0 +1,243 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BalanceSnapshot struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Currency string    `json:"currency"`
+	Balance  float64   `json:"balance"`
+	Locked   float64   `json:"locked"`
+	Staked   float64   `json:"staked"`
+	At       time.Time `json:"at"`
+}
+
+type BalanceChanged struct {
+	Previous     BalanceSnapshot
+	Current      BalanceSnapshot
+	BalanceDelta float64
+	LockedDelta  float64
+	StakedDelta  float64
+}
+
+// SnapshotStore persists the latest snapshot of every wallet.
+type SnapshotStore interface {
+	Latest(ctx context.Context, userID uuid.UUID, currency string) (BalanceSnapshot, bool, error)
+	Save(ctx context.Context, snapshot BalanceSnapshot) error
+}
+
+var _ SnapshotStore = (*MemorySnapshotStore)(nil)
+
+type MemorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]BalanceSnapshot
+}
+
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{snapshots: make(map[string]BalanceSnapshot)}
+}
+
+func (s *MemorySnapshotStore) Latest(ctx context.Context, userID uuid.UUID, currency string) (BalanceSnapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.snapshots[userID.String()+"/"+currency]
+	return snapshot, ok, nil
+}
+
+func (s *MemorySnapshotStore) Save(ctx context.Context, snapshot BalanceSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[snapshot.UserID.String()+"/"+snapshot.Currency] = snapshot
+	return nil
+}
+
+// Snapshotter periodically captures the wallets of a set of users and reports the balances that changed.
+type Snapshotter struct {
+	client      Client
+	store       SnapshotStore
+	users       []uuid.UUID
+	currencies  []string
+	concurrency int
+	interval    time.Duration
+	onChange    func(ctx context.Context, event BalanceChanged)
+	onError     func(err error)
+	now         func() time.Time
+}
+
+// SnapshotterOption is a function that configures a Snapshotter.
+type SnapshotterOption func(*Snapshotter)
+
+// WithSnapshotCurrencies limits the captured wallets to the currencies.
+func WithSnapshotCurrencies(currencies ...string) SnapshotterOption {
+	return func(target *Snapshotter) {
+		target.currencies = currencies
+	}
+}
+
+// WithSnapshotConcurrency sets how many users are captured at the same time.
+func WithSnapshotConcurrency(n int) SnapshotterOption {
+	return func(target *Snapshotter) {
+		target.concurrency = n
+	}
+}
+
+// WithSnapshotInterval sets how often Run captures the wallets.
+func WithSnapshotInterval(d time.Duration) SnapshotterOption {
+	return func(target *Snapshotter) {
+		target.interval = d
+	}
+}
+
+// WithBalanceChanged sets the callback invoked for every wallet that changed since the previous snapshot.
+func WithBalanceChanged(fn func(ctx context.Context, event BalanceChanged)) SnapshotterOption {
+	return func(target *Snapshotter) {
+		target.onChange = fn
+	}
+}
+
+// WithSnapshotError sets the callback invoked with the errors of the captures of Run.
+func WithSnapshotError(fn func(err error)) SnapshotterOption {
+	return func(target *Snapshotter) {
+		target.onError = fn
+	}
+}
+
+func NewSnapshotter(client Client, store SnapshotStore, users []uuid.UUID, options ...SnapshotterOption) *Snapshotter {
+	s := &Snapshotter{
+		client:      client,
+		store:       store,
+		users:       users,
+		concurrency: 8,
+		interval:    time.Minute,
+		now:         time.Now,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Run captures the wallets every interval until ctx is done.
+func (s *Snapshotter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		// errors of a single capture are reported and retried on the next tick
+		if _, err := s.Capture(ctx); err != nil && s.onError != nil && ctx.Err() == nil {
+			s.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Capture takes one snapshot of every wallet and returns the changes since the previous one.
+// Wallets without a previous snapshot are saved without reporting a change.
+func (s *Snapshotter) Capture(ctx context.Context) ([]BalanceChanged, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		changes []BalanceChanged
+		errs    []error
+	)
+
+	sem := make(chan struct{}, max(s.concurrency, 1))
+
+	for _, userID := range s.users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			c, err := s.capture(ctx, userID)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			changes = append(changes, c...)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if s.onChange != nil {
+		for _, c := range changes {
+			s.onChange(ctx, c)
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+func (s *Snapshotter) capture(ctx context.Context, userID uuid.UUID) ([]BalanceChanged, error) {
+	wallets, err := s.client.ForUser(userID).FetchWallets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallets of %s: %w", userID, err)
+	}
+
+	var changes []BalanceChanged
+
+	for _, w := range wallets.Data {
+		currency := strings.ToLower(w.Currency)
+		if len(s.currencies) > 0 && !slices.ContainsFunc(s.currencies, func(c string) bool { return strings.EqualFold(c, currency) }) {
+			continue
+		}
+
+		current := BalanceSnapshot{
+			UserID:   userID,
+			Currency: currency,
+			Balance:  w.GetBalance(),
+			Locked:   w.GetLocked(),
+			Staked:   w.GetStaked(),
+			At:       s.now(),
+		}
+
+		previous, ok, err := s.store.Latest(ctx, userID, currency)
+		if err != nil {
+			return changes, fmt.Errorf("failed to load snapshot: %w", err)
+		}
+
+		if err := s.store.Save(ctx, current); err != nil {
+			return changes, fmt.Errorf("failed to save snapshot: %w", err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		c := BalanceChanged{
+			Previous:     previous,
+			Current:      current,
+			BalanceDelta: current.Balance - previous.Balance,
+			LockedDelta:  current.Locked - previous.Locked,
+			StakedDelta:  current.Staked - previous.Staked,
+		}
+
+		if c.BalanceDelta != 0 || c.LockedDelta != 0 || c.StakedDelta != 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,77 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotter_Capture(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	userID := uuid.MustParse("8269672d-d451-4ad2-88ac-bd70f1133615")
+	path := "/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/wallets"
+
+	var events []quidax.BalanceChanged
+	snapshotter := quidax.NewSnapshotter(client, quidax.NewMemorySnapshotStore(), []uuid.UUID{userID},
+		quidax.WithSnapshotCurrencies("BTC", "ETH"),
+		quidax.WithBalanceChanged(func(ctx context.Context, event quidax.BalanceChanged) {
+			events = append(events, event)
+		}),
+	)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", requestPath(path)).Return(resp, nil).Once()
+
+	changes, err := snapshotter.Capture(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchPortfolioOk))}
+	mockHttpClient.On("Do", requestPath(path)).Return(resp, nil).Once()
+
+	changes, err = snapshotter.Capture(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "btc", changes[0].Current.Currency)
+	assert.Equal(t, -9.0, changes[0].BalanceDelta)
+	assert.Equal(t, 0.5, changes[0].LockedDelta)
+	assert.Equal(t, changes, events)
+}
+
+func TestSnapshotter_RunReportsErrors(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(nil, errors.New("connection reset"))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	var errs []error
+	snapshotter := quidax.NewSnapshotter(client, quidax.NewMemorySnapshotStore(), []uuid.UUID{uuid.New()},
+		quidax.WithSnapshotInterval(time.Millisecond),
+		quidax.WithSnapshotError(func(err error) {
+			errs = append(errs, err)
+			if len(errs) == 2 {
+				cancel()
+			}
+		}),
+	)
+
+	err := snapshotter.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "connection reset")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 type SwapClient interface {
 	Quote(ctx context.Context, userID uuid.UUID, payload QuotePayload) (QuoteResponse, error)
 	ConfirmQuote(ctx context.Context, userID, quoteID uuid.UUID) error
+	FetchSwapTransactions(ctx context.Context, userID uuid.UUID, page int) (SwapTransactionsResponse, error)
 }
 
 type QuotePayload struct {
@@ -43,29 +45,67 @@ func (d QuoteData) GetToAmount() float64 {
 	return f
 }
 
-type QuoteResponse struct {
-	Status  string    `json:"status"`
-	Message string    `json:"message"`
-	Data    QuoteData `json:"data"`
+type QuoteResponse = Response[QuoteData]
+
+type SwapTransactionData struct {
+	ID             string    `json:"id"`
+	FromCurrency   string    `json:"from_currency"`
+	ToCurrency     string    `json:"to_currency"`
+	FromAmount     string    `json:"from_amount"`
+	ReceivedAmount string    `json:"received_amount"`
+	ExecutionPrice string    `json:"execution_price"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	SwapQuotation  QuoteData `json:"swap_quotation"`
+}
+
+func (d SwapTransactionData) GetFromAmount() float64 {
+	f, _ := strconv.ParseFloat(d.FromAmount, 64)
+	return f
+}
+
+func (d SwapTransactionData) GetReceivedAmount() float64 {
+	f, _ := strconv.ParseFloat(d.ReceivedAmount, 64)
+	return f
 }
 
 func (c *client) Quote(ctx context.Context, userID uuid.UUID, payload QuotePayload) (data QuoteResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/v1/users/%s/swap_quotation", userID), payload)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
+	return c.quote(ctx, userID.String(), payload)
+}
+
+func (c *client) quote(ctx context.Context, user string, payload QuotePayload) (data QuoteResponse, err error) {
+	defer c.audit(ctx, AuditQuote, user, payload, &data, &err, time.Now())
+
+	if r := c.validator(); r != nil {
+		if err := r.ValidateQuote(payload); err != nil {
+			return data, err
+		}
 	}
 
-	req.ExpectStatus(http.StatusCreated)
-	req.DecodeTo(&data)
-	return data, c.do(ctx, req)
+	return call[QuoteData](ctx, c, http.MethodPost, fmt.Sprintf("/v1/users/%s/swap_quotation", user), nil, payload, http.StatusCreated)
 }
 
 func (c *client) ConfirmQuote(ctx context.Context, userID, quoteID uuid.UUID) error {
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/v1/users/%s/swap_quotation/%s/confirm", userID, quoteID), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.confirmQuote(ctx, userID.String(), quoteID)
+}
+
+func (c *client) confirmQuote(ctx context.Context, user string, quoteID uuid.UUID) (err error) {
+	defer c.audit(ctx, AuditConfirmQuote, user, map[string]uuid.UUID{"quote_id": quoteID}, nil, &err, time.Now())
+
+	return c.call(ctx, http.MethodPost, fmt.Sprintf("/v1/users/%s/swap_quotation/%s/confirm", user, quoteID), nil, nil, nil, http.StatusCreated)
+}
+
+type SwapTransactionsResponse = Response[[]SwapTransactionData]
+
+// FetchSwapTransactions returns a page of the swap transactions of the user.
+func (c *client) FetchSwapTransactions(ctx context.Context, userID uuid.UUID, page int) (data SwapTransactionsResponse, err error) {
+	return c.fetchSwapTransactions(ctx, userID.String(), page)
+}
+
+func (c *client) fetchSwapTransactions(ctx context.Context, user string, page int) (data SwapTransactionsResponse, err error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	query.Set("page", strconv.Itoa(page))
 
-	req.ExpectStatus(http.StatusCreated)
-	return c.do(ctx, req)
+	return call[[]SwapTransactionData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/swap_transactions", user), query, nil, http.StatusOK)
 }
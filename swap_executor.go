@@ -0,0 +1,212 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSwapTransactionNotFound is returned by SwapExecutor when the quote was confirmed,
+// but the resulting swap transaction is not listed yet. The swap must not be retried.
+var ErrSwapTransactionNotFound = errors.New("swap transaction of the confirmed quote not found")
+
+type SwapAbortReason string
+
+const (
+	SwapAbortSlippageExceeded SwapAbortReason = "slippage_exceeded"
+	SwapAbortQuoteExpired     SwapAbortReason = "quote_expired"
+)
+
+// SwapAbortedError is returned by SwapExecutor when the swap was not confirmed.
+type SwapAbortedError struct {
+	Reason        SwapAbortReason
+	Quote         QuoteData
+	Rate          float64
+	ReferenceRate float64
+}
+
+func (e SwapAbortedError) Error() string {
+	return fmt.Sprintf("Swap aborted. Reason: %s Rate: %g Reference rate: %g", e.Reason, e.Rate, e.ReferenceRate)
+}
+
+// RateProvider returns the reference rate for converting one unit of from into to.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// RateProviderFunc is an adapter to allow the use of ordinary functions as RateProvider.
+type RateProviderFunc func(ctx context.Context, from, to string) (float64, error)
+
+func (f RateProviderFunc) Rate(ctx context.Context, from, to string) (float64, error) {
+	return f(ctx, from, to)
+}
+
+// GetRate returns the rate implied by the quote, how much of ToCurrency one unit of FromCurrency buys.
+func (d QuoteData) GetRate() float64 {
+	from := d.GetFromAmount()
+	if from == 0 {
+		return 0
+	}
+	return d.GetToAmount() / from
+}
+
+type SwapResult struct {
+	Quote       QuoteData
+	Transaction SwapTransactionData
+	Attempts    int
+}
+
+type SwapExecutor struct {
+	client       SwapClient
+	rates        RateProvider
+	maxSlippage  float64
+	safetyMargin time.Duration
+	maxRequotes  int
+	now          func() time.Time
+}
+
+// SwapExecutorOption is a function that configures a SwapExecutor.
+type SwapExecutorOption func(*SwapExecutor)
+
+// WithReferenceRate sets the provider the quoted rate is compared against.
+// Without it, requotes are compared against the rate of the first quote.
+func WithReferenceRate(p RateProvider) SwapExecutorOption {
+	return func(target *SwapExecutor) {
+		target.rates = p
+	}
+}
+
+// WithMaxSlippage sets the maximum accepted slippage as a fraction, e.g. 0.02 for 2%, defaults to 1%.
+func WithMaxSlippage(f float64) SwapExecutorOption {
+	return func(target *SwapExecutor) {
+		target.maxSlippage = f
+	}
+}
+
+// WithSafetyMargin sets how long before ExpiresAt a quote is considered expired.
+func WithSafetyMargin(d time.Duration) SwapExecutorOption {
+	return func(target *SwapExecutor) {
+		target.safetyMargin = d
+	}
+}
+
+// WithMaxRequotes sets how many times an expired quote is requested again.
+func WithMaxRequotes(n int) SwapExecutorOption {
+	return func(target *SwapExecutor) {
+		target.maxRequotes = n
+	}
+}
+
+// WithSwapClock sets the function used to get the current time.
+func WithSwapClock(now func() time.Time) SwapExecutorOption {
+	return func(target *SwapExecutor) {
+		target.now = now
+	}
+}
+
+func NewSwapExecutor(client SwapClient, options ...SwapExecutorOption) *SwapExecutor {
+	e := &SwapExecutor{
+		client:       client,
+		maxSlippage:  0.01,
+		safetyMargin: 2 * time.Second,
+		maxRequotes:  3,
+		now:          time.Now,
+	}
+
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// Execute requests a quote, confirms it as long as the rate is within the slippage threshold,
+// and returns the resulting swap transaction.
+func (e *SwapExecutor) Execute(ctx context.Context, userID uuid.UUID, payload QuotePayload) (result SwapResult, err error) {
+	var (
+		baseline float64
+		last     QuoteData
+	)
+
+	for attempt := 1; attempt <= e.maxRequotes+1; attempt++ {
+		resp, err := e.client.Quote(ctx, userID, payload)
+		if err != nil {
+			return result, fmt.Errorf("failed to request quote: %w", err)
+		}
+
+		quote := resp.Data
+		rate := quote.GetRate()
+
+		reference := baseline
+		if e.rates != nil {
+			reference, err = e.rates.Rate(ctx, quote.FromCurrency, quote.ToCurrency)
+			if err != nil {
+				return result, fmt.Errorf("failed to get reference rate: %w", err)
+			}
+		} else if attempt == 1 {
+			baseline, reference = rate, rate
+		}
+
+		if reference > 0 && (reference-rate)/reference > e.maxSlippage {
+			return result, SwapAbortedError{Reason: SwapAbortSlippageExceeded, Quote: quote, Rate: rate, ReferenceRate: reference}
+		}
+
+		if !dryRun(e.client) && !e.now().Add(e.safetyMargin).Before(quote.ExpiresAt) {
+			last = quote
+			continue
+		}
+
+		if err := e.client.ConfirmQuote(ctx, userID, quote.ID); err != nil {
+			return result, fmt.Errorf("failed to confirm quote: %w", err)
+		}
+
+		quote.Confirmed = true
+		result = SwapResult{Quote: quote, Attempts: attempt}
+
+		result.Transaction, err = e.transaction(ctx, userID, quote)
+		return result, err
+	}
+
+	return result, SwapAbortedError{Reason: SwapAbortQuoteExpired, Quote: last, Rate: last.GetRate(), ReferenceRate: baseline}
+}
+
+// transaction looks up the swap transaction created by confirming the quote.
+func (e *SwapExecutor) transaction(ctx context.Context, userID uuid.UUID, quote QuoteData) (SwapTransactionData, error) {
+	if dryRun(e.client) {
+		return SwapTransactionData{
+			ID:             quote.ID.String(),
+			FromCurrency:   quote.FromCurrency,
+			ToCurrency:     quote.ToCurrency,
+			FromAmount:     quote.FromAmount,
+			ReceivedAmount: quote.ToAmount,
+			ExecutionPrice: strconv.FormatFloat(quote.GetRate(), 'f', -1, 64),
+			Status:         DryRunMessage,
+			CreatedAt:      e.now(),
+			SwapQuotation:  quote,
+		}, nil
+	}
+
+	// the transaction was just created, so it is on the first page
+	resp, err := e.client.FetchSwapTransactions(ctx, userID, 1)
+	if err != nil {
+		return SwapTransactionData{}, fmt.Errorf("failed to fetch swap transactions: %w", err)
+	}
+
+	for _, t := range resp.Data {
+		if t.SwapQuotation.ID == quote.ID {
+			return t, nil
+		}
+	}
+
+	return SwapTransactionData{}, fmt.Errorf("%w: quote %s", ErrSwapTransactionNotFound, quote.ID)
+}
+
+// dryRun reports whether the client only pretends to send mutating requests, see WithDryRun.
+func dryRun(c interface{}) bool {
+	d, ok := c.(interface{ isDryRun() bool })
+	return ok && d.isDryRun()
+}
@@ -0,0 +1,141 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func swapClock(value string) func() time.Time {
+	return func() time.Time {
+		t, _ := time.Parse(time.RFC3339, value)
+		return t
+	}
+}
+
+func TestSwapExecutor_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	executor := quidax.NewSwapExecutor(client, quidax.WithSwapClock(swapClock("2025-10-10T07:10:15Z")))
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	resp2 := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp2, nil).Once()
+
+	resp3 := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(swapTransactionsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp3, nil).Once()
+
+	got, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{})
+	require.NoError(t, err)
+	assert.True(t, got.Quote.Confirmed)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "7d1a4c2e-9b8f-4e3d-a2c1-5f6e7d8c9b0a", got.Transaction.ID)
+	assert.Equal(t, "0.00034847", got.Transaction.ReceivedAmount)
+}
+
+func TestSwapExecutor_TransactionNotFound(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	executor := quidax.NewSwapExecutor(client, quidax.WithSwapClock(swapClock("2025-10-10T07:10:15Z")))
+
+	for _, b := range [][]byte{swapQuoteOk, swapQuoteOk} {
+		resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(b))}
+		mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+	}
+
+	empty := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"success","message":"Successful","data":[]}`)))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(empty, nil).Once()
+
+	got, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{})
+	assert.ErrorIs(t, err, quidax.ErrSwapTransactionNotFound)
+	assert.True(t, got.Quote.Confirmed)
+}
+
+func TestSwapExecutor_DefaultMaxSlippage(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	rates := quidax.RateProviderFunc(func(ctx context.Context, from, to string) (float64, error) {
+		return 0.0355, nil
+	})
+
+	executor := quidax.NewSwapExecutor(client, quidax.WithSwapClock(swapClock("2025-10-10T07:10:15Z")), quidax.WithReferenceRate(rates))
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{})
+
+	var aborted quidax.SwapAbortedError
+	require.ErrorAs(t, err, &aborted)
+	assert.Equal(t, quidax.SwapAbortSlippageExceeded, aborted.Reason)
+}
+
+func TestSwapExecutor_DryRun(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun())
+	executor := quidax.NewSwapExecutor(client, quidax.WithSwapClock(swapClock("2025-10-10T07:10:15Z")))
+
+	got, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{FromCurrency: "eth", ToCurrency: "btc", FromAmount: "0.01"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, quidax.DryRunMessage, got.Transaction.Status)
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestSwapExecutor_SlippageExceeded(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	rates := quidax.RateProviderFunc(func(ctx context.Context, from, to string) (float64, error) {
+		return 0.04, nil
+	})
+
+	executor := quidax.NewSwapExecutor(client,
+		quidax.WithSwapClock(swapClock("2025-10-10T07:10:15Z")),
+		quidax.WithReferenceRate(rates),
+		quidax.WithMaxSlippage(0.01),
+	)
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	_, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{})
+
+	var aborted quidax.SwapAbortedError
+	require.ErrorAs(t, err, &aborted)
+	assert.Equal(t, quidax.SwapAbortSlippageExceeded, aborted.Reason)
+	assert.Equal(t, 0.04, aborted.ReferenceRate)
+}
+
+func TestSwapExecutor_QuoteExpired(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+	executor := quidax.NewSwapExecutor(client,
+		quidax.WithSwapClock(swapClock("2025-10-10T07:10:26Z")),
+		quidax.WithMaxRequotes(1),
+	)
+
+	for range 2 {
+		resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+		mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+	}
+
+	_, err := executor.Execute(context.TODO(), uuid.New(), quidax.QuotePayload{})
+
+	var aborted quidax.SwapAbortedError
+	require.ErrorAs(t, err, &aborted)
+	assert.Equal(t, quidax.SwapAbortQuoteExpired, aborted.Reason)
+}
@@ -42,3 +42,17 @@ func TestConfirmQuote_Success(t *testing.T) {
 	err := client.ConfirmQuote(context.TODO(), uuid.New(), uuid.New())
 	require.NoError(t, err)
 }
+
+func TestFetchSwapTransactions_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(swapTransactionsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchSwapTransactions(context.TODO(), uuid.New(), 1)
+	require.NoError(t, err)
+	require.Len(t, got.Data, 1)
+	assert.Equal(t, 0.00034847, got.Data[0].GetReceivedAmount())
+	assert.True(t, got.Data[0].SwapQuotation.Confirmed)
+}
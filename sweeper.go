@@ -0,0 +1,194 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SweepResult struct {
+	AccountID  uuid.UUID      `json:"account_id"`
+	Currency   string         `json:"currency"`
+	Available  float64        `json:"available"`
+	Fee        float64        `json:"fee"`
+	Amount     string         `json:"amount,omitempty"`
+	Reference  string         `json:"reference,omitempty"`
+	Withdrawal WithdrawalData `json:"withdrawal"`
+	Skipped    string         `json:"skipped,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+type SweepReport struct {
+	RunID      string        `json:"run_id"`
+	DryRun     bool          `json:"dry_run"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Results    []SweepResult `json:"results"`
+}
+
+// Swept returns the results that moved, or in dry-run mode would move, funds.
+func (r SweepReport) Swept() []SweepResult {
+	swept := make([]SweepResult, 0)
+	for _, res := range r.Results {
+		if res.Amount != "" && res.Error == "" {
+			swept = append(swept, res)
+		}
+	}
+	return swept
+}
+
+// Sweeper moves sub-account balances above a per-currency threshold into the parent account.
+type Sweeper struct {
+	client     Client
+	thresholds map[string]float64
+	dryRun     bool
+	runID      string
+	now        func() time.Time
+}
+
+// SweeperOption is a function that configures a Sweeper.
+type SweeperOption func(*Sweeper)
+
+// WithSweepDryRun makes the Sweeper report what it would move without creating withdrawals.
+func WithSweepDryRun() SweeperOption {
+	return func(target *Sweeper) {
+		target.dryRun = true
+	}
+}
+
+// WithSweepRunID sets the run ID the withdrawal references are derived from, it defaults to the current date.
+// Running the Sweeper twice with the same run ID never moves the same balance twice.
+func WithSweepRunID(id string) SweeperOption {
+	return func(target *Sweeper) {
+		target.runID = id
+	}
+}
+
+func NewSweeper(client Client, thresholds map[string]float64, options ...SweeperOption) *Sweeper {
+	s := &Sweeper{
+		client:     client,
+		thresholds: make(map[string]float64, len(thresholds)),
+		now:        time.Now,
+	}
+
+	for currency, threshold := range thresholds {
+		s.thresholds[strings.ToLower(currency)] = threshold
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+func (s *Sweeper) Run(ctx context.Context) (report SweepReport, err error) {
+	report = SweepReport{RunID: s.runID, DryRun: s.dryRun, StartedAt: s.now()}
+	if report.RunID == "" {
+		report.RunID = report.StartedAt.UTC().Format("20060102")
+	}
+
+	parent, err := s.client.FetchParentAccount(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to fetch parent account: %w", err)
+	}
+
+	currencies := make([]string, 0, len(s.thresholds))
+	for currency := range s.thresholds {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	schedules := make(map[string]FeeSchedule)
+
+	for page := 1; ; page++ {
+		accounts, err := s.client.FetchAccounts(ctx, page)
+		if err != nil {
+			return report, fmt.Errorf("failed to fetch accounts: %w", err)
+		}
+
+		if len(accounts.Data) == 0 {
+			break
+		}
+
+		for _, account := range accounts.Data {
+			if account.ID == parent.Data.ID {
+				continue
+			}
+
+			for _, currency := range currencies {
+				if _, ok := schedules[currency]; !ok {
+					fees, err := s.client.FetchWithdrawalFees(ctx, currency, "")
+					if err != nil {
+						return report, fmt.Errorf("failed to fetch %s fees: %w", currency, err)
+					}
+					schedules[currency] = fees.GetFeeSchedule()
+				}
+
+				res := s.sweep(ctx, report.RunID, parent.Data.ID, account.ID, currency, schedules[currency])
+				report.Results = append(report.Results, res)
+			}
+		}
+	}
+
+	report.FinishedAt = s.now()
+	return report, nil
+}
+
+func (s *Sweeper) sweep(ctx context.Context, runID string, parentID, accountID uuid.UUID, currency string, schedule FeeSchedule) SweepResult {
+	res := SweepResult{AccountID: accountID, Currency: currency}
+	user := s.client.ForUser(accountID)
+
+	wallet, err := user.FetchWallet(ctx, currency)
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to fetch wallet: %v", err)
+		return res
+	}
+
+	res.Available = wallet.Data.GetAvailable()
+	if res.Available <= s.thresholds[currency] {
+		res.Skipped = "below threshold"
+		return res
+	}
+
+	amount := schedule.NetFor(res.Available, precisionOf(s.client, currency))
+	if amount <= 0 {
+		res.Skipped = "balance does not cover the fee"
+		return res
+	}
+
+	if len(schedule) > 0 {
+		if res.Fee, err = schedule.FeeFor(amount); err != nil {
+			res.Error = fmt.Sprintf("failed to calculate fee: %v", err)
+			return res
+		}
+	}
+
+	res.Amount = strconv.FormatFloat(amount, 'f', -1, 64)
+	res.Reference = fmt.Sprintf("sweep-%s-%s-%s", runID, accountID, currency)
+
+	if s.dryRun {
+		return res
+	}
+
+	resp, err := user.CreateWithdrawal(ctx, CreateWithdrawalPayload{
+		Currency:        currency,
+		Amount:          res.Amount,
+		TransactionNote: "Sweep to parent account",
+		Narration:       "Sweep to parent account",
+		FundUID:         parentID.String(),
+		Reference:       res.Reference,
+	})
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to create withdrawal: %v", err)
+		return res
+	}
+
+	res.Withdrawal = resp.Data
+	return res
+}
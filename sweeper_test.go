@@ -0,0 +1,112 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/accounts-fetch-parent-ok.json
+var accountsFetchParentOK []byte
+
+//go:embed testdata/accounts-fetch-all-empty.json
+var accountsFetchAllEmpty []byte
+
+func accountsPage(page string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/users" && req.URL.Query().Get("page") == page
+	})
+}
+
+func mockSweep(mockHttpClient *quidax.MockHttpClient) {
+	responses := []struct {
+		match  interface{}
+		status int
+		body   []byte
+	}{
+		{requestPath("/v1/users/me"), http.StatusOK, accountsFetchParentOK},
+		{accountsPage("1"), http.StatusOK, accountsFetchAllOK},
+		{accountsPage("2"), http.StatusOK, accountsFetchAllEmpty},
+		{requestPath("/v1/fee"), http.StatusOK, feesOne},
+		{requestPath("/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/wallets/btc"), http.StatusOK, walletsFetchBtcOk},
+	}
+
+	for _, r := range responses {
+		resp := &http.Response{StatusCode: r.status, Body: io.NopCloser(bytes.NewReader(r.body))}
+		mockHttpClient.On("Do", r.match).Return(resp, nil).Once()
+	}
+}
+
+func TestSweeper_DryRun(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockSweep(mockHttpClient)
+
+	sweeper := quidax.NewSweeper(client, map[string]float64{"BTC": 1}, quidax.WithSweepDryRun(), quidax.WithSweepRunID("run"))
+
+	got, err := sweeper.Run(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, got.Swept(), 1)
+	assert.Equal(t, 5.0, got.Swept()[0].Available)
+	assert.Equal(t, 1.0, got.Swept()[0].Fee)
+	assert.Equal(t, "4", got.Swept()[0].Amount)
+	assert.Equal(t, "sweep-run-8269672d-d451-4ad2-88ac-bd70f1133615-btc", got.Swept()[0].Reference)
+}
+
+func TestSweeper_UppercaseThreshold(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockSweep(mockHttpClient)
+
+	got, err := quidax.NewSweeper(client, map[string]float64{"BTC": 5}, quidax.WithSweepDryRun()).Run(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, got.Swept())
+	require.Len(t, got.Results, 1)
+	assert.Equal(t, "btc", got.Results[0].Currency)
+	assert.Equal(t, "below threshold", got.Results[0].Skipped)
+}
+
+func TestSweeper_Run(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockSweep(mockHttpClient)
+
+	withdrawal := mock.MatchedBy(func(req *http.Request) bool {
+		if req.URL.Path != "/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/withdraws" {
+			return false
+		}
+
+		var payload quidax.CreateWithdrawalPayload
+		b, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		return json.Unmarshal(b, &payload) == nil && payload.FundUID == "0b3f9c1e-6a51-4c0b-9d0c-3c8a2f4d7e21" && payload.Amount == "4"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", withdrawal).Return(resp, nil).Once()
+
+	got, err := quidax.NewSweeper(client, map[string]float64{"btc": 1}).Run(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, got.Swept(), 1)
+	assert.Equal(t, quidax.WithdrawalStatusProcessing, got.Swept()[0].Withdrawal.Status)
+}
+
+func TestSweeper_BelowThreshold(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+	mockSweep(mockHttpClient)
+
+	got, err := quidax.NewSweeper(client, map[string]float64{"btc": 100}).Run(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, got.Swept())
+	assert.Equal(t, "below threshold", got.Results[0].Skipped)
+}
@@ -0,0 +1,137 @@
+package quidax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNoToken = errors.New("no token available")
+
+// TokenProvider returns the bearer token, it is consulted on every request.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenRotator is a TokenProvider that can replace a token the API rejected.
+// The client calls Rotate once when a request fails with 401 and retries it with the new token.
+type TokenRotator interface {
+	TokenProvider
+	Rotate(ctx context.Context, rejected string) (string, error)
+}
+
+// TokenProviderFunc adapts a function to TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken returns a provider that always returns the token.
+func StaticToken(token string) TokenProvider {
+	return TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvToken returns a provider that reads the token from the environment variable on every request.
+func EnvToken(name string) TokenProvider {
+	return TokenProviderFunc(func(ctx context.Context) (string, error) {
+		token := strings.TrimSpace(os.Getenv(name))
+		if token == "" {
+			return "", fmt.Errorf("%w: %s is not set", ErrNoToken, name)
+		}
+		return token, nil
+	})
+}
+
+var _ TokenProvider = (*FileTokenProvider)(nil)
+
+// FileTokenProvider reads the token from a file and reloads it whenever the file changes,
+// so a key can be rotated by replacing the file.
+type FileTokenProvider struct {
+	mu      sync.Mutex
+	path    string
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func NewFileTokenProvider(path string) *FileTokenProvider {
+	return &FileTokenProvider{path: path}
+}
+
+func (p *FileTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat token file: %w", err)
+	}
+
+	if p.token != "" && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.token, nil
+	}
+
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrNoToken, p.path)
+	}
+
+	p.token, p.modTime, p.size = token, info.ModTime(), info.Size()
+	return p.token, nil
+}
+
+var _ TokenRotator = (*RotatingTokenProvider)(nil)
+
+// RotatingTokenProvider returns the current token and asks the callback for a new one when the API rejects it.
+type RotatingTokenProvider struct {
+	mu     sync.Mutex
+	token  string
+	rotate func(ctx context.Context, rejected string) (string, error)
+}
+
+func NewRotatingTokenProvider(token string, rotate func(ctx context.Context, rejected string) (string, error)) *RotatingTokenProvider {
+	return &RotatingTokenProvider{token: token, rotate: rotate}
+}
+
+func (p *RotatingTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.token, nil
+}
+
+// Rotate replaces the rejected token, concurrent requests rejected with the same token rotate it only once.
+func (p *RotatingTokenProvider) Rotate(ctx context.Context, rejected string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != rejected {
+		return p.token, nil
+	}
+
+	token, err := p.rotate(ctx, rejected)
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate token: %w", err)
+	}
+
+	p.token = token
+	return p.token, nil
+}
+
+// WithTokenProvider sets the provider of the bearer token, replacing the token passed to NewClient.
+func WithTokenProvider(p TokenProvider) ClientOption {
+	return func(target *client) {
+		target.tokens = p
+	}
+}
@@ -0,0 +1,114 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func bearer(token string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Bearer "+token
+	})
+}
+
+func TestEnvToken(t *testing.T) {
+	t.Setenv("QUIDAX_TEST_TOKEN", "")
+
+	_, err := quidax.EnvToken("QUIDAX_TEST_TOKEN").Token(context.TODO())
+	assert.ErrorIs(t, err, quidax.ErrNoToken)
+
+	t.Setenv("QUIDAX_TEST_TOKEN", "env-token")
+
+	got, err := quidax.EnvToken("QUIDAX_TEST_TOKEN").Token(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", got)
+}
+
+func TestWithTokenProvider_DryRunWithoutToken(t *testing.T) {
+	t.Setenv("QUIDAX_TEST_TOKEN", "")
+
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("", quidax.WithHTTPClient(mockHttpClient), quidax.WithDryRun(), quidax.WithTokenProvider(quidax.EnvToken("QUIDAX_TEST_TOKEN")))
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+
+	_, err = client.FetchParentAccount(context.TODO())
+	assert.ErrorIs(t, err, quidax.ErrNoToken)
+
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestFileTokenProvider_Reloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+
+	p := quidax.NewFileTokenProvider(path)
+
+	got, err := p.Token(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	require.NoError(t, os.WriteFile(path, []byte("second-token\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	got, err = p.Token(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "second-token", got)
+}
+
+func TestWithTokenProvider_RotatesOn401(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+
+	rotations := 0
+	provider := quidax.NewRotatingTokenProvider("old", func(ctx context.Context, rejected string) (string, error) {
+		rotations++
+		assert.Equal(t, "old", rejected)
+		return "new", nil
+	})
+
+	client := quidax.NewClient("", quidax.WithHTTPClient(mockHttpClient), quidax.WithTokenProvider(provider))
+
+	unauthorized := &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Unauthorized"}`)))}
+	ok := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+
+	mockHttpClient.On("Do", bearer("old")).Return(unauthorized, nil).Once()
+	mockHttpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		b, _ := io.ReadAll(req.Body)
+		return req.Header.Get("Authorization") == "Bearer new" && bytes.Contains(b, []byte(`"currency":"btc"`))
+	})).Return(ok, nil).Once()
+
+	_, err := client.CreateWithdrawal(context.TODO(), uuid.New(), quidax.CreateWithdrawalPayload{Currency: "btc", Amount: "0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rotations)
+
+	token, err := provider.Token(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "new", token)
+}
+
+func TestWithTokenProvider_StaticTokenIsNotRetried(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	unauthorized := &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewReader([]byte(`{"status":"error","message":"Unauthorized"}`)))}
+	mockHttpClient.On("Do", bearer("token")).Return(unauthorized, nil).Once()
+
+	_, err := client.FetchWallet(context.TODO(), uuid.New(), "btc")
+
+	var errResponse quidax.ErrResponse
+	require.ErrorAs(t, err, &errResponse)
+	assert.Equal(t, http.StatusUnauthorized, errResponse.StatusCode)
+}
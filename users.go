@@ -0,0 +1,103 @@
+package quidax
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// UserClient is scoped to a single account, so its methods don't take the user ID.
+type UserClient interface {
+	FetchAccount(ctx context.Context) (AccountResponse, error)
+	UpdateAccount(ctx context.Context, payload UpdateAccountPayload) (AccountResponse, error)
+	FetchWallet(ctx context.Context, currency string) (WalletResponse, error)
+	FetchWallets(ctx context.Context) (WalletsResponse, error)
+	FetchWalletAddress(ctx context.Context, currency string) (WalletAddressResponse, error)
+	FetchWalletAddresses(ctx context.Context, currency string) (WalletAddressesResponse, error)
+	RequestWalletAddress(ctx context.Context, currency, network string) (WalletAddressResponse, error)
+	CreateWithdrawal(ctx context.Context, payload CreateWithdrawalPayload) (WithdrawalResponse, error)
+	FetchWithdrawal(ctx context.Context, id string) (WithdrawalResponse, error)
+	FetchWithdrawalByReference(ctx context.Context, reference string) (WithdrawalResponse, error)
+	FetchWithdrawals(ctx context.Context, currency, state string, page int) (WithdrawalsResponse, error)
+	FetchDeposits(ctx context.Context, currency, state string, page int) (DepositsResponse, error)
+	Quote(ctx context.Context, payload QuotePayload) (QuoteResponse, error)
+	ConfirmQuote(ctx context.Context, quoteID uuid.UUID) error
+	FetchSwapTransactions(ctx context.Context, page int) (SwapTransactionsResponse, error)
+}
+
+var _ UserClient = (*userClient)(nil)
+
+type userClient struct {
+	client *client
+	user   string
+}
+
+// ForUser returns a client scoped to the sub-account.
+func (c *client) ForUser(id uuid.UUID) UserClient {
+	return &userClient{client: c, user: id.String()}
+}
+
+// ForParent returns a client scoped to the parent account.
+func (c *client) ForParent() UserClient {
+	return &userClient{client: c, user: ParentAccountID}
+}
+
+func (u *userClient) FetchAccount(ctx context.Context) (AccountResponse, error) {
+	return u.client.fetchAccount(ctx, u.user)
+}
+
+func (u *userClient) UpdateAccount(ctx context.Context, payload UpdateAccountPayload) (AccountResponse, error) {
+	return u.client.updateAccount(ctx, u.user, payload)
+}
+
+func (u *userClient) FetchWallet(ctx context.Context, currency string) (WalletResponse, error) {
+	return u.client.fetchWallet(ctx, u.user, currency)
+}
+
+func (u *userClient) FetchWallets(ctx context.Context) (WalletsResponse, error) {
+	return u.client.fetchWallets(ctx, u.user)
+}
+
+func (u *userClient) FetchWalletAddress(ctx context.Context, currency string) (WalletAddressResponse, error) {
+	return u.client.fetchWalletAddress(ctx, u.user, currency)
+}
+
+func (u *userClient) FetchWalletAddresses(ctx context.Context, currency string) (WalletAddressesResponse, error) {
+	return u.client.fetchWalletAddresses(ctx, u.user, currency)
+}
+
+func (u *userClient) RequestWalletAddress(ctx context.Context, currency, network string) (WalletAddressResponse, error) {
+	return u.client.requestWalletAddress(ctx, u.user, currency, network)
+}
+
+func (u *userClient) CreateWithdrawal(ctx context.Context, payload CreateWithdrawalPayload) (WithdrawalResponse, error) {
+	return u.client.createWithdrawal(ctx, u.user, payload)
+}
+
+func (u *userClient) FetchWithdrawal(ctx context.Context, id string) (WithdrawalResponse, error) {
+	return u.client.fetchWithdrawal(ctx, u.user, id)
+}
+
+func (u *userClient) FetchWithdrawalByReference(ctx context.Context, reference string) (WithdrawalResponse, error) {
+	return u.client.fetchWithdrawalByReference(ctx, u.user, reference)
+}
+
+func (u *userClient) FetchWithdrawals(ctx context.Context, currency, state string, page int) (WithdrawalsResponse, error) {
+	return u.client.fetchWithdrawals(ctx, u.user, currency, state, page)
+}
+
+func (u *userClient) FetchDeposits(ctx context.Context, currency, state string, page int) (DepositsResponse, error) {
+	return u.client.fetchDeposits(ctx, u.user, currency, state, page)
+}
+
+func (u *userClient) Quote(ctx context.Context, payload QuotePayload) (QuoteResponse, error) {
+	return u.client.quote(ctx, u.user, payload)
+}
+
+func (u *userClient) ConfirmQuote(ctx context.Context, quoteID uuid.UUID) error {
+	return u.client.confirmQuote(ctx, u.user, quoteID)
+}
+
+func (u *userClient) FetchSwapTransactions(ctx context.Context, page int) (SwapTransactionsResponse, error) {
+	return u.client.fetchSwapTransactions(ctx, u.user, page)
+}
@@ -0,0 +1,60 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func requestPath(path string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == path
+	})
+}
+
+func TestForUser_FetchWallet(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	id := uuid.New()
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchBtcOk))}
+	mockHttpClient.On("Do", requestPath("/v1/users/"+id.String()+"/wallets/btc")).Return(resp, nil).Once()
+
+	got, err := client.ForUser(id).FetchWallet(context.TODO(), "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 10.00, got.Data.GetBalance())
+}
+
+func TestForParent_FetchWallets(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", requestPath("/v1/users/me/wallets")).Return(resp, nil).Once()
+
+	got, err := client.ForParent().FetchWallets(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, got.Data, 1)
+}
+
+func TestForParent_ConfirmQuote(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	quoteID := uuid.New()
+
+	resp := &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(swapQuoteOk))}
+	mockHttpClient.On("Do", requestPath("/v1/users/me/swap_quotation/"+quoteID.String()+"/confirm")).Return(resp, nil).Once()
+
+	err := client.ForParent().ConfirmQuote(context.TODO(), quoteID)
+	require.NoError(t, err)
+}
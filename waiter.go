@@ -0,0 +1,109 @@
+package quidax
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minPollInterval keeps zero or negative backoffs from polling the API in a tight loop.
+const minPollInterval = 10 * time.Millisecond
+
+type waitConfig struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	onChange    func(WithdrawalData)
+}
+
+// WaitOption is a function that configures how a resource is polled.
+type WaitOption func(*waitConfig)
+
+// WithBackoff sets the first poll interval, it is doubled after every poll up to max.
+// Intervals below 10ms are raised to it.
+func WithBackoff(interval, max time.Duration) WaitOption {
+	return func(target *waitConfig) {
+		target.interval = interval
+		target.maxInterval = max
+	}
+}
+
+// WithWithdrawalProgress sets the callback invoked every time the withdrawal status changes.
+func WithWithdrawalProgress(fn func(WithdrawalData)) WaitOption {
+	return func(target *waitConfig) {
+		target.onChange = fn
+	}
+}
+
+func newWaitConfig(options []WaitOption) waitConfig {
+	cfg := waitConfig{
+		interval:    time.Second,
+		maxInterval: 30 * time.Second,
+	}
+
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	cfg.interval = max(cfg.interval, minPollInterval)
+	cfg.maxInterval = max(cfg.maxInterval, cfg.interval)
+
+	return cfg
+}
+
+// poll calls fn until it reports done, sleeping with exponential backoff in between.
+func (cfg waitConfig) poll(ctx context.Context, fn func() (bool, error)) error {
+	interval := cfg.interval
+
+	for {
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, cfg.maxInterval)
+	}
+}
+
+// WaitForWithdrawal polls the withdrawal until it reaches a terminal status or ctx is done.
+func WaitForWithdrawal(ctx context.Context, client WithdrawalsClient, userID uuid.UUID, id string, options ...WaitOption) (WithdrawalData, error) {
+	return waitForWithdrawal(ctx, options, func() (WithdrawalResponse, error) {
+		return client.FetchWithdrawal(ctx, userID, id)
+	})
+}
+
+// WaitForWithdrawalByReference polls the withdrawal until it reaches a terminal status or ctx is done.
+func WaitForWithdrawalByReference(ctx context.Context, client WithdrawalsClient, userID uuid.UUID, reference string, options ...WaitOption) (WithdrawalData, error) {
+	return waitForWithdrawal(ctx, options, func() (WithdrawalResponse, error) {
+		return client.FetchWithdrawalByReference(ctx, userID, reference)
+	})
+}
+
+func waitForWithdrawal(ctx context.Context, options []WaitOption, fetch func() (WithdrawalResponse, error)) (data WithdrawalData, err error) {
+	cfg := newWaitConfig(options)
+
+	err = cfg.poll(ctx, func() (bool, error) {
+		resp, err := fetch()
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch withdrawal: %w", err)
+		}
+
+		if resp.Data.Status != data.Status && cfg.onChange != nil {
+			cfg.onChange(resp.Data)
+		}
+
+		data = resp.Data
+		return data.IsTerminal(), nil
+	})
+
+	return data, err
+}
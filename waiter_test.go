@@ -0,0 +1,66 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForWithdrawal_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	for _, b := range [][]byte{withdrawalsFetchProcessing, withdrawalsFetchProcessing, withdrawalsFetchDone} {
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(b))}
+		mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+	}
+
+	var changes []string
+	got, err := quidax.WaitForWithdrawal(context.TODO(), client, uuid.New(), "9e7b5c45-70d4-4aa6-8a84-df1a2d0e4c41",
+		quidax.WithBackoff(time.Millisecond, time.Millisecond),
+		quidax.WithWithdrawalProgress(func(d quidax.WithdrawalData) { changes = append(changes, d.Status) }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, quidax.WithdrawalStatusDone, got.Status)
+	assert.Equal(t, []string{quidax.WithdrawalStatusProcessing, quidax.WithdrawalStatusDone}, changes)
+}
+
+func TestWaitForWithdrawalByReference_ContextDone(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	got, err := quidax.WaitForWithdrawalByReference(ctx, client, uuid.New(), "ref-123", quidax.WithBackoff(time.Hour, time.Hour))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, quidax.WithdrawalStatusProcessing, got.Status)
+}
+
+func TestWaitForWithdrawal_ZeroBackoffIsClamped(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 25*time.Millisecond)
+	defer cancel()
+
+	_, err := quidax.WaitForWithdrawal(ctx, client, uuid.New(), "9e7b5c45-70d4-4aa6-8a84-df1a2d0e4c41", quidax.WithBackoff(0, -time.Second))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.LessOrEqual(t, len(mockHttpClient.Calls), 4)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -11,16 +12,20 @@ import (
 )
 
 type WalletData struct {
-	ID                uuid.UUID `json:"id"`
-	Name              string    `json:"name"`
-	Balance           string    `json:"balance"`
-	Locked            string    `json:"locked"`
-	Staked            string    `json:"staked"`
-	IsCrypto          bool      `json:"is_crypto"`
-	BlockchainEnabled bool      `json:"blockchain_enabled"`
-	DefaultNetwork    string    `json:"default_network"`
-	DepositAddress    string    `json:"deposit_address"`
-	DestinationTag    string    `json:"destination_tag"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Currency string    `json:"currency"`
+	// Balance is the total balance of the wallet, Locked is the part of it held by pending orders and withdrawals.
+	Balance           string `json:"balance"`
+	Locked            string `json:"locked"`
+	Staked            string `json:"staked"`
+	ConvertedBalance  string `json:"converted_balance"`
+	ReferenceCurrency string `json:"reference_currency"`
+	IsCrypto          bool   `json:"is_crypto"`
+	BlockchainEnabled bool   `json:"blockchain_enabled"`
+	DefaultNetwork    string `json:"default_network"`
+	DepositAddress    string `json:"deposit_address"`
+	DestinationTag    string `json:"destination_tag"`
 	Networks          []struct {
 		ID               string `json:"id"`
 		Name             string `json:"name"`
@@ -39,17 +44,24 @@ func (d WalletData) GetLocked() float64 {
 	return f
 }
 
+// GetAvailable returns the part of the balance that is not locked.
+func (d WalletData) GetAvailable() float64 {
+	return d.GetBalance() - d.GetLocked()
+}
+
 func (d WalletData) GetStaked() float64 {
 	f, _ := strconv.ParseFloat(d.Staked, 64)
 	return f
 }
 
-type WalletResponse struct {
-	Status  string     `json:"status"`
-	Message string     `json:"message"`
-	Data    WalletData `json:"data"`
+// GetConvertedBalance returns the balance converted by the API into ReferenceCurrency.
+func (d WalletData) GetConvertedBalance() float64 {
+	f, _ := strconv.ParseFloat(d.ConvertedBalance, 64)
+	return f
 }
 
+type WalletResponse = Response[WalletData]
+
 type WalletsClient interface {
 	FetchWallet(ctx context.Context, id uuid.UUID, currency string) (WalletResponse, error)
 	FetchWallets(ctx context.Context, id uuid.UUID) (WalletsResponse, error)
@@ -59,31 +71,21 @@ type WalletsClient interface {
 }
 
 func (c *client) FetchWallet(ctx context.Context, id uuid.UUID, currency string) (data WalletResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s", id, strings.ToLower(currency)), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	return c.fetchWallet(ctx, id.String(), currency)
 }
 
-type WalletsResponse struct {
-	Status  string        `json:"status"`
-	Message string        `json:"message"`
-	Data    []AccountData `json:"data"`
+func (c *client) fetchWallet(ctx context.Context, user, currency string) (data WalletResponse, err error) {
+	return call[WalletData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s", user, strings.ToLower(currency)), nil, nil, http.StatusOK)
 }
 
+type WalletsResponse = Response[[]WalletData]
+
 func (c *client) FetchWallets(ctx context.Context, id uuid.UUID) (data WalletsResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/v1/users", nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.fetchWallets(ctx, id.String())
+}
 
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+func (c *client) fetchWallets(ctx context.Context, user string) (data WalletsResponse, err error) {
+	return call[[]WalletData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets", user), nil, nil, http.StatusOK)
 }
 
 type WalletAddressData struct {
@@ -95,51 +97,42 @@ type WalletAddressData struct {
 	DestinationTag string    `json:"destination_tag"`
 }
 
-type WalletAddressResponse struct {
-	Status  string            `json:"status"`
-	Message string            `json:"message"`
-	Data    WalletAddressData `json:"data"`
-}
+type WalletAddressResponse = Response[WalletAddressData]
 
 func (c *client) FetchWalletAddress(ctx context.Context, id uuid.UUID, currency string) (data WalletAddressResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s/address", id, strings.ToLower(currency)), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	return c.fetchWalletAddress(ctx, id.String(), currency)
 }
 
-type WalletAddressesResponse struct {
-	Status  string              `json:"status"`
-	Message string              `json:"message"`
-	Data    []WalletAddressData `json:"data"`
+func (c *client) fetchWalletAddress(ctx context.Context, user, currency string) (data WalletAddressResponse, err error) {
+	return call[WalletAddressData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s/address", user, strings.ToLower(currency)), nil, nil, http.StatusOK)
 }
 
+type WalletAddressesResponse = Response[[]WalletAddressData]
+
 func (c *client) FetchWalletAddresses(ctx context.Context, id uuid.UUID, currency string) (data WalletAddressesResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s/addresses", id, strings.ToLower(currency)), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.fetchWalletAddresses(ctx, id.String(), currency)
+}
 
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+func (c *client) fetchWalletAddresses(ctx context.Context, user, currency string) (data WalletAddressesResponse, err error) {
+	return call[[]WalletAddressData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/wallets/%s/addresses", user, strings.ToLower(currency)), nil, nil, http.StatusOK)
 }
 
 func (c *client) RequestWalletAddress(ctx context.Context, id uuid.UUID, currency, network string) (data WalletAddressResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/v1/users/%s/wallets/%s/addresses", id, strings.ToLower(currency)), nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
+	return c.requestWalletAddress(ctx, id.String(), currency, network)
+}
+
+func (c *client) requestWalletAddress(ctx context.Context, user, currency, network string) (data WalletAddressResponse, err error) {
+	if r := c.validator(); r != nil {
+		if err := r.ValidateNetwork(currency, network); err != nil {
+			return data, err
+		}
 	}
 
+	query := url.Values{}
+
 	if network != "" {
-		req.AddQueryParam("network", network)
+		query.Set("network", network)
 	}
 
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusCreated)
-	return data, c.do(ctx, req)
+	return call[WalletAddressData](ctx, c, http.MethodPost, fmt.Sprintf("/v1/users/%s/wallets/%s/addresses", user, strings.ToLower(currency)), query, nil, http.StatusCreated)
 }
@@ -54,6 +54,27 @@ func TestFetchWallets_Success(t *testing.T) {
 	assert.Len(t, got.Data, 1)
 }
 
+// Regression: wallets were fetched from /v1/users and decoded as accounts.
+func TestFetchWallets_PathAndWalletData(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	userID := uuid.New()
+	walletsPath := mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.Path == "/api/v1/users/"+userID.String()+"/wallets"
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchAllOk))}
+	mockHttpClient.On("Do", walletsPath).Return(resp, nil).Once()
+
+	got, err := client.FetchWallets(context.TODO(), userID)
+	require.NoError(t, err)
+	require.Len(t, got.Data, 1)
+	assert.IsType(t, quidax.WalletData{}, got.Data[0])
+	assert.Equal(t, "btc", got.Data[0].Currency)
+	assert.Equal(t, 10.0, got.Data[0].GetBalance())
+}
+
 func TestFetchWalletAddress_Success(t *testing.T) {
 	mockHttpClient := quidax.NewMockHttpClient(t)
 	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
@@ -0,0 +1,301 @@
+package quidax
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const WebhookSignatureHeader = "quidax-signature"
+
+const (
+	EventDepositSuccessful        = "deposit.successful"
+	EventDepositOnHold            = "deposit.on_hold"
+	EventDepositFailedAML         = "deposit.failed_aml"
+	EventDepositConfirmation      = "deposit.transaction.confirmation"
+	EventWithdrawSuccessful       = "withdraw.successful"
+	EventWithdrawRejected         = "withdraw.rejected"
+	EventSwapTransactionCompleted = "swap_transaction.completed"
+	EventSwapTransactionReversed  = "swap_transaction.reversed"
+	EventSwapTransactionFailed    = "swap_transaction.failed"
+	EventWalletAddressGenerated   = "wallet.address.generated"
+)
+
+const maxWebhookBodySize = 1 << 20
+
+var (
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrStaleWebhook            = errors.New("stale webhook delivery")
+	ErrReplayedWebhook         = errors.New("replayed webhook delivery")
+)
+
+type WebhookEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// WebhookHandlerFunc handles a verified webhook event, returning an error makes Quidax retry the delivery.
+type WebhookHandlerFunc func(ctx context.Context, event WebhookEvent) error
+
+var _ http.Handler = (*WebhookHandler)(nil)
+
+type WebhookHandler struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+	logger    *logrus.Logger
+
+	mu       sync.Mutex
+	seen     map[string]delivery
+	handlers map[string]WebhookHandlerFunc
+}
+
+// delivery is a verified signature, handled is set once the handler succeeded.
+type delivery struct {
+	at      time.Time
+	handled bool
+}
+
+// WebhookOption is a function that configures a WebhookHandler.
+type WebhookOption func(*WebhookHandler)
+
+// WithWebhookTolerance sets how old a delivery can be before it is rejected as stale.
+func WithWebhookTolerance(d time.Duration) WebhookOption {
+	return func(target *WebhookHandler) {
+		target.tolerance = d
+	}
+}
+
+// WithWebhookClock sets the function used to get the current time.
+func WithWebhookClock(now func() time.Time) WebhookOption {
+	return func(target *WebhookHandler) {
+		target.now = now
+	}
+}
+
+// WithWebhookLogger sets the *logrus.Logger for the WebhookHandler.
+func WithWebhookLogger(l *logrus.Logger) WebhookOption {
+	return func(target *WebhookHandler) {
+		target.logger = l
+	}
+}
+
+func NewWebhookHandler(secret string, options ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{
+		secret:    []byte(secret),
+		tolerance: 5 * time.Minute,
+		now:       time.Now,
+		seen:      make(map[string]delivery),
+		handlers:  make(map[string]WebhookHandlerFunc),
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+// Handle registers the handler for the event type.
+func (h *WebhookHandler) Handle(event string, fn WebhookHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[event] = fn
+}
+
+func handleTyped[T any](h *WebhookHandler, fn func(ctx context.Context, event string, data T) error, events []string) {
+	for _, event := range events {
+		h.Handle(event, func(ctx context.Context, e WebhookEvent) error {
+			var data T
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				return fmt.Errorf("failed to decode %s event: %w", e.Event, err)
+			}
+			return fn(ctx, e.Event, data)
+		})
+	}
+}
+
+// OnDeposit registers the handler for deposit events, all of them if none are given.
+func (h *WebhookHandler) OnDeposit(fn func(ctx context.Context, event string, data DepositData) error, events ...string) {
+	if len(events) == 0 {
+		events = []string{EventDepositSuccessful, EventDepositOnHold, EventDepositFailedAML, EventDepositConfirmation}
+	}
+	handleTyped(h, fn, events)
+}
+
+// OnWithdrawal registers the handler for withdrawal events, all of them if none are given.
+func (h *WebhookHandler) OnWithdrawal(fn func(ctx context.Context, event string, data WithdrawalData) error, events ...string) {
+	if len(events) == 0 {
+		events = []string{EventWithdrawSuccessful, EventWithdrawRejected}
+	}
+	handleTyped(h, fn, events)
+}
+
+// OnSwap registers the handler for swap transaction events, all of them if none are given.
+func (h *WebhookHandler) OnSwap(fn func(ctx context.Context, event string, data SwapTransactionData) error, events ...string) {
+	if len(events) == 0 {
+		events = []string{EventSwapTransactionCompleted, EventSwapTransactionReversed, EventSwapTransactionFailed}
+	}
+	handleTyped(h, fn, events)
+}
+
+// OnWalletAddress registers the handler for the address generation event.
+func (h *WebhookHandler) OnWalletAddress(fn func(ctx context.Context, event string, data WalletAddressData) error) {
+	handleTyped(h, fn, []string{EventWalletAddressGenerated})
+}
+
+// Verify checks the signature header, which has the "t=<unix timestamp>,s=<hex hmac-sha256>" format,
+// the signed payload is the timestamp and the body joined with a dot.
+// Accepted signatures are remembered for the tolerance window so a delivery can't be replayed.
+func (h *WebhookHandler) Verify(header string, body []byte) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "s":
+			signature = v
+		}
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil || timestamp == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(timestamp + "." + string(body)))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+
+	now := h.now()
+	if now.Sub(time.Unix(ts, 0)).Abs() > h.tolerance {
+		return ErrStaleWebhook
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for k, d := range h.seen {
+		if now.Sub(d.at) > h.tolerance {
+			delete(h.seen, k)
+		}
+	}
+
+	if _, ok := h.seen[signature]; ok {
+		return ErrReplayedWebhook
+	}
+
+	h.seen[signature] = delivery{at: now}
+	return nil
+}
+
+func signatureOf(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		if k, v, _ := strings.Cut(strings.TrimSpace(part), "="); k == "s" {
+			return v
+		}
+	}
+	return ""
+}
+
+// forget allows the delivery to be retried after it failed to be handled.
+func (h *WebhookHandler) forget(header string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.seen, signatureOf(header))
+}
+
+// markHandled remembers the delivery was handled, so a retry of it is acknowledged without handling it again.
+func (h *WebhookHandler) markHandled(header string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if d, ok := h.seen[signatureOf(header)]; ok {
+		d.handled = true
+		h.seen[signatureOf(header)] = d
+	}
+}
+
+func (h *WebhookHandler) handled(header string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seen[signatureOf(header)].handled
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	header := r.Header.Get(WebhookSignatureHeader)
+	if err := h.Verify(header, body); err != nil {
+		if h.logger != nil {
+			h.logger.WithContext(r.Context()).WithError(err).Warn("quidax.webhook -> rejected")
+		}
+
+		// Quidax retries deliveries whose response it didn't get, those are acknowledged again,
+		// a retry of one that is still being handled is asked to come back later
+		if errors.Is(err, ErrReplayedWebhook) {
+			if h.handled(header) {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	fn, ok := h.handlers[event.Event]
+	h.mu.Unlock()
+
+	if ok {
+		if err := fn(r.Context(), event); err != nil {
+			if h.logger != nil {
+				h.logger.WithContext(r.Context()).WithError(err).WithField("quidax.webhook.event", event.Event).Error("quidax.webhook -> handler failed")
+			}
+
+			h.forget(header)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.markHandled(header)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,132 @@
+package quidax_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/webhook-withdraw-successful.json
+var webhookWithdrawSuccessful []byte
+
+func signWebhook(secret string, at time.Time, body []byte) string {
+	ts := fmt.Sprintf("%d", at.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "." + string(body)))
+	return fmt.Sprintf("t=%s,s=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func webhookRequest(signature string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set(quidax.WebhookSignatureHeader, signature)
+	return req
+}
+
+func TestWebhookHandler_Dispatch(t *testing.T) {
+	now := time.Date(2025, 10, 10, 7, 20, 12, 0, time.UTC)
+	handler := quidax.NewWebhookHandler("secret", quidax.WithWebhookClock(func() time.Time { return now }))
+
+	var got quidax.WithdrawalData
+	calls := 0
+	handler.OnWithdrawal(func(ctx context.Context, event string, data quidax.WithdrawalData) error {
+		got = data
+		calls++
+		return nil
+	})
+
+	signature := signWebhook("secret", now, webhookWithdrawSuccessful)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, webhookRequest(signature, webhookWithdrawSuccessful))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ref-123", got.Reference)
+	assert.Equal(t, quidax.WithdrawalStatusDone, got.Status)
+
+	// a retry of a handled delivery is acknowledged without handling it again
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, webhookRequest(signature, webhookWithdrawSuccessful))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWebhookHandler_RetryWhileHandling(t *testing.T) {
+	now := time.Date(2025, 10, 10, 7, 20, 12, 0, time.UTC)
+	handler := quidax.NewWebhookHandler("secret", quidax.WithWebhookClock(func() time.Time { return now }))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler.Handle(quidax.EventWithdrawSuccessful, func(ctx context.Context, event quidax.WebhookEvent) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	signature := signWebhook("secret", now, webhookWithdrawSuccessful)
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(first, webhookRequest(signature, webhookWithdrawSuccessful))
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, webhookRequest(signature, webhookWithdrawSuccessful))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+}
+
+func TestWebhookHandler_HandlerFailureAllowsRetry(t *testing.T) {
+	now := time.Date(2025, 10, 10, 7, 20, 12, 0, time.UTC)
+	handler := quidax.NewWebhookHandler("secret", quidax.WithWebhookClock(func() time.Time { return now }))
+
+	calls := 0
+	handler.Handle(quidax.EventWithdrawSuccessful, func(ctx context.Context, event quidax.WebhookEvent) error {
+		calls++
+		if calls == 1 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	signature := signWebhook("secret", now, webhookWithdrawSuccessful)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, webhookRequest(signature, webhookWithdrawSuccessful))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, webhookRequest(signature, webhookWithdrawSuccessful))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestWebhookHandler_Verify(t *testing.T) {
+	now := time.Date(2025, 10, 10, 7, 20, 12, 0, time.UTC)
+	handler := quidax.NewWebhookHandler("secret", quidax.WithWebhookClock(func() time.Time { return now }))
+
+	err := handler.Verify(signWebhook("other", now, webhookWithdrawSuccessful), webhookWithdrawSuccessful)
+	require.ErrorIs(t, err, quidax.ErrInvalidWebhookSignature)
+
+	err = handler.Verify(signWebhook("secret", now.Add(-time.Hour), webhookWithdrawSuccessful), webhookWithdrawSuccessful)
+	require.ErrorIs(t, err, quidax.ErrStaleWebhook)
+
+	err = handler.Verify("garbage", webhookWithdrawSuccessful)
+	require.ErrorIs(t, err, quidax.ErrInvalidWebhookSignature)
+}
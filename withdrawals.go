@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,39 +16,124 @@ import (
 type WithdrawalsClient interface {
 	FetchWithdrawalFees(ctx context.Context, currency, network string) (FeesResponse, error)
 	CreateWithdrawal(ctx context.Context, userID uuid.UUID, payload CreateWithdrawalPayload) (WithdrawalResponse, error)
+	FetchWithdrawal(ctx context.Context, userID uuid.UUID, id string) (WithdrawalResponse, error)
+	FetchWithdrawalByReference(ctx context.Context, userID uuid.UUID, reference string) (WithdrawalResponse, error)
+	FetchWithdrawals(ctx context.Context, userID uuid.UUID, currency, state string, page int) (WithdrawalsResponse, error)
 }
 
+const (
+	WithdrawalStatusSubmitted  = "submitted"
+	WithdrawalStatusProcessing = "processing"
+	WithdrawalStatusDone       = "done"
+	WithdrawalStatusFailed     = "failed"
+	WithdrawalStatusRejected   = "rejected"
+	WithdrawalStatusCanceled   = "canceled"
+)
+
 type WithdrawalData struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
-	Reason string `json:"reason"`
+	ID        string    `json:"id"`
+	Reference string    `json:"reference"`
+	Currency  string    `json:"currency"`
+	Amount    string    `json:"amount"`
+	Fee       string    `json:"fee"`
+	TxID      string    `json:"txid"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (d WithdrawalData) GetAmount() float64 {
+	f, _ := strconv.ParseFloat(d.Amount, 64)
+	return f
 }
 
-type WithdrawalResponse struct {
-	Status  string         `json:"status"`
-	Message string         `json:"message"`
-	Data    WithdrawalData `json:"data"`
+// IsTerminal reports whether the withdrawal reached a state it will not leave.
+func (d WithdrawalData) IsTerminal() bool {
+	switch d.Status {
+	case WithdrawalStatusDone, WithdrawalStatusFailed, WithdrawalStatusRejected, WithdrawalStatusCanceled:
+		return true
+	}
+	return false
 }
 
+type WithdrawalResponse = Response[WithdrawalData]
+
 type CreateWithdrawalPayload struct {
 	Currency        string `json:"currency"`
 	Amount          string `json:"amount"`
 	TransactionNote string `json:"transaction_note"`
 	Narration       string `json:"narration"`
 	FundUID         string `json:"fund_uid"`
-	Network         string `json:"network"`
-	Reference       string `json:"reference"`
+	// FundUID2 is the destination tag or memo, required by exchanges for currencies like XRP, XLM and BNB on BEP2.
+	// It is only enforced for clients created WithRegistry, and for addresses added with Registry.AddExchangeAddresses.
+	FundUID2  string `json:"fund_uid2,omitempty"`
+	Network   string `json:"network"`
+	Reference string `json:"reference"`
 }
 
 func (c *client) CreateWithdrawal(ctx context.Context, userID uuid.UUID, payload CreateWithdrawalPayload) (data WithdrawalResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/v1/users/%s/withdraws", userID), payload)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
+	return c.createWithdrawal(ctx, userID.String(), payload)
+}
+
+func (c *client) createWithdrawal(ctx context.Context, user string, payload CreateWithdrawalPayload) (data WithdrawalResponse, err error) {
+	defer c.audit(ctx, AuditCreateWithdrawal, user, payload, &data, &err, time.Now())
+
+	if r := c.validator(); r != nil {
+		if err := r.ValidateWithdrawal(payload); err != nil {
+			return data, err
+		}
+	}
+
+	send := func() (WithdrawalResponse, error) {
+		return call[WithdrawalData](ctx, c, http.MethodPost, fmt.Sprintf("/v1/users/%s/withdraws", user), nil, payload, http.StatusCreated)
+	}
+
+	if c.guard != nil {
+		// the parent account is evaluated as uuid.Nil
+		userID, _ := uuid.Parse(user)
+		return c.guard.run(ctx, userID, payload, c.dryRun, send)
 	}
 
-	req.ExpectStatus(http.StatusCreated)
-	req.DecodeTo(&data)
-	return data, c.do(ctx, req)
+	return send()
+}
+
+func (c *client) FetchWithdrawal(ctx context.Context, userID uuid.UUID, id string) (data WithdrawalResponse, err error) {
+	return c.fetchWithdrawal(ctx, userID.String(), id)
+}
+
+func (c *client) fetchWithdrawal(ctx context.Context, user, id string) (data WithdrawalResponse, err error) {
+	return call[WithdrawalData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/withdraws/%s", user, id), nil, nil, http.StatusOK)
+}
+
+func (c *client) FetchWithdrawalByReference(ctx context.Context, userID uuid.UUID, reference string) (data WithdrawalResponse, err error) {
+	return c.fetchWithdrawalByReference(ctx, userID.String(), reference)
+}
+
+func (c *client) fetchWithdrawalByReference(ctx context.Context, user, reference string) (data WithdrawalResponse, err error) {
+	return call[WithdrawalData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/withdraws/reference/%s", user, url.PathEscape(reference)), nil, nil, http.StatusOK)
+}
+
+type WithdrawalsResponse = Response[[]WithdrawalData]
+
+// FetchWithdrawals returns a page of the withdrawals of the user, currency and state are optional filters.
+func (c *client) FetchWithdrawals(ctx context.Context, userID uuid.UUID, currency, state string, page int) (data WithdrawalsResponse, err error) {
+	return c.fetchWithdrawals(ctx, userID.String(), currency, state, page)
+}
+
+func (c *client) fetchWithdrawals(ctx context.Context, user, currency, state string, page int) (data WithdrawalsResponse, err error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	query.Set("page", strconv.Itoa(page))
+
+	if currency != "" {
+		query.Set("currency", strings.ToLower(currency))
+	}
+
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	return call[[]WithdrawalData](ctx, c, http.MethodGet, fmt.Sprintf("/v1/users/%s/withdraws", user), query, nil, http.StatusOK)
 }
 
 type Fee struct {
@@ -65,11 +153,8 @@ type MultiFeeData struct {
 	Fees []Fee  `json:"fee"`
 }
 
-type FeesResponse struct {
-	Status  string          `json:"status"`
-	Message string          `json:"message"`
-	Data    json.RawMessage `json:"data"`
-}
+// FeesResponse is its own type, as the fee data comes in several shapes, see GetFees.
+type FeesResponse Response[json.RawMessage]
 
 func (r FeesResponse) GetFees() []Fee {
 	fees := make([]Fee, 0)
@@ -88,18 +173,31 @@ func (r FeesResponse) GetFees() []Fee {
 }
 
 func (c *client) FetchWithdrawalFees(ctx context.Context, currency, network string) (data FeesResponse, err error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/v1/fee", nil)
-	if err != nil {
-		return data, fmt.Errorf("failed to create request: %w", err)
+	query := url.Values{}
+	query.Set("currency", strings.ToLower(currency))
+
+	if network != "" {
+		query.Set("network", strings.ToLower(network))
 	}
 
-	req.AddQueryParam("currency", strings.ToLower(currency))
+	resp, err := call[json.RawMessage](ctx, c, http.MethodGet, "/v1/fee", query, nil, http.StatusOK)
+	return FeesResponse(resp), err
+}
 
-	if network != "" {
-		req.AddQueryParam("network", strings.ToLower(network))
+// MaxWithdrawable returns the largest amount of the currency the user can withdraw on the network,
+// after the fee is taken from the available balance. It can be used as CreateWithdrawalPayload.Amount.
+func MaxWithdrawable(ctx context.Context, client Client, userID uuid.UUID, currency, network string) (string, error) {
+	wallet, err := client.FetchWallet(ctx, userID, currency)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch wallet: %w", err)
 	}
 
-	req.DecodeTo(&data)
-	req.ExpectStatus(http.StatusOK)
-	return data, c.do(ctx, req)
+	fees, err := client.FetchWithdrawalFees(ctx, currency, network)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch withdrawal fees: %w", err)
+	}
+
+	amount := fees.GetFeeSchedule().NetFor(wallet.Data.GetAvailable(), precisionOf(client, currency))
+
+	return strconv.FormatFloat(amount, 'f', -1, 64), nil
 }
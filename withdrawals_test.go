@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/brokeyourbike/quidax-api-client-go"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,12 @@ var feesOne []byte
 //go:embed testdata/fees-multi.json
 var feesMulti []byte
 
+//go:embed testdata/withdrawals-fetch-processing.json
+var withdrawalsFetchProcessing []byte
+
+//go:embed testdata/withdrawals-fetch-done.json
+var withdrawalsFetchDone []byte
+
 func TestFetchWithdrawalFees_One(t *testing.T) {
 	mockHttpClient := quidax.NewMockHttpClient(t)
 	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
@@ -45,3 +52,54 @@ func TestFetchWithdrawalFees_Multi(t *testing.T) {
 	assert.Equal(t, "success", got.Status)
 	assert.Len(t, got.GetFees(), 7)
 }
+
+func TestFetchWithdrawal_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchDone))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchWithdrawal(context.TODO(), uuid.New(), "9e7b5c45-70d4-4aa6-8a84-df1a2d0e4c41")
+	require.NoError(t, err)
+	assert.Equal(t, quidax.WithdrawalStatusDone, got.Data.Status)
+	assert.True(t, got.Data.IsTerminal())
+}
+
+func TestFetchWithdrawalByReference_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchProcessing))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchWithdrawalByReference(context.TODO(), uuid.New(), "ref-123")
+	require.NoError(t, err)
+	assert.Equal(t, "ref-123", got.Data.Reference)
+	assert.False(t, got.Data.IsTerminal())
+}
+
+func TestFetchWithdrawals_Success(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(withdrawalsFetchAllOk))}
+	mockHttpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(resp, nil).Once()
+
+	got, err := client.FetchWithdrawals(context.TODO(), uuid.New(), "btc", quidax.WithdrawalStatusDone, 1)
+	require.NoError(t, err)
+	assert.Len(t, got.Data, 3)
+	assert.Equal(t, 0.01, got.Data[0].GetAmount())
+}
+
+func TestMaxWithdrawable(t *testing.T) {
+	mockHttpClient := quidax.NewMockHttpClient(t)
+	client := quidax.NewClient("token", quidax.WithHTTPClient(mockHttpClient), quidax.WithBaseURL("https://example.com"))
+
+	mockHttpClient.On("Do", requestPath("/v1/users/8269672d-d451-4ad2-88ac-bd70f1133615/wallets/btc")).Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(walletsFetchBtcOk))}, nil).Once()
+	mockHttpClient.On("Do", requestPath("/v1/fee")).Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(feesMulti))}, nil).Once()
+
+	got, err := quidax.MaxWithdrawable(context.TODO(), client, uuid.MustParse("8269672d-d451-4ad2-88ac-bd70f1133615"), "btc", "btc")
+	require.NoError(t, err)
+	assert.Equal(t, "4.9995", got)
+}